@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/forge-build/forge-provider-gcp/cmd/forge-provider-gcp/app/options"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/scope"
 	gcpbuildcontroller "github.com/forge-build/forge-provider-gcp/pkg/controllers/gcpbuild"
 )
 
@@ -43,5 +44,10 @@ func createAllControllers(ctrlCtx *options.ControllerContext) error {
 }
 
 func createGCPBuildController(ctrlCtx *options.ControllerContext) error {
-	return gcpbuildcontroller.Add(ctrlCtx.Ctx, ctrlCtx.Mgr, 1, &ctrlCtx.Log)
+	endpoints := scope.ServiceEndpoints{
+		Storage: ctrlCtx.RunOptions.StorageEndpoint,
+		OSLogin: ctrlCtx.RunOptions.OSLoginEndpoint,
+	}
+
+	return gcpbuildcontroller.Add(ctrlCtx.Ctx, ctrlCtx.Mgr, 1, endpoints, &ctrlCtx.Log)
 }
@@ -33,6 +33,8 @@ type ControllerManagerRunOptions struct {
 	LogLevel             log.LogLevel
 	LogFormat            log.Format
 	WorkerName           string
+	StorageEndpoint      string
+	OSLoginEndpoint      string
 }
 
 type ControllerContext struct {
@@ -49,4 +51,6 @@ func (o *ControllerManagerRunOptions) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&o.MetricsBindAddress, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	fs.StringVar(&o.WorkerName, "worker-name", "", "The name of the worker that will only processes resources with label=worker-name.")
 	fs.Var(&o.LogFormat, "log-format", "Log format, one of [Console, Json]")
+	fs.StringVar(&o.StorageEndpoint, "storage-endpoint", "", "Override the Cloud Storage API endpoint, e.g. to use a fake GCS server.")
+	fs.StringVar(&o.OSLoginEndpoint, "oslogin-endpoint", "", "Override the OS Login API endpoint, e.g. to use a fake OS Login server.")
 }
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+const (
+	// SourceImageResolvedCondition reports on the resolution of the image the builder instance is created from.
+	SourceImageResolvedCondition clusterv1.ConditionType = "SourceImageResolved"
+
+	// SourceImageNotSpecifiedReason used when neither an image, an image family nor an image filter is specified.
+	SourceImageNotSpecifiedReason = "SourceImageNotSpecified"
+	// SourceImageInvalidReason used when the image or image family reference can't be parsed.
+	SourceImageInvalidReason = "SourceImageInvalid"
+	// SourceImageNotFoundReason used when the image or image family does not exist, or no image matches the filter.
+	SourceImageNotFoundReason = "SourceImageNotFound"
+	// SourceImageResolutionFailedReason used when the image can't be resolved for any other reason.
+	SourceImageResolutionFailedReason = "SourceImageResolutionFailed"
+	// WaitingForSourceBuildReason used when the GCPBuild the image comes from is not ready yet.
+	WaitingForSourceBuildReason = "WaitingForSourceBuild"
+	// SourceBuildFailedReason used when the GCPBuild the image comes from has failed, it is the failure reason
+	// of the build as well.
+	SourceBuildFailedReason = "SourceBuildFailed"
+)
+
+const (
+	// InstanceReadyCondition reports on the readiness of the builder instance to accept connections.
+	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
+
+	// WaitingForInstanceRunningReason used when the builder instance is not RUNNING yet.
+	WaitingForInstanceRunningReason = "WaitingForInstanceRunning"
+	// WaitingForGuestAttributeReason used when the guest attribute of the readiness gate is not set yet.
+	WaitingForGuestAttributeReason = "WaitingForGuestAttribute"
+)
+
+const (
+	// NetworkReadyCondition reports on the reconciliation of the network of the build.
+	NetworkReadyCondition clusterv1.ConditionType = "NetworkReady"
+	// NetworkReconciliationFailedReason used when the network can't be reconciled.
+	NetworkReconciliationFailedReason = "NetworkReconciliationFailed"
+	// NetworkCreatingReason used while the network or the router is being created.
+	NetworkCreatingReason = "NetworkCreating"
+
+	// FirewallsReadyCondition reports on the reconciliation of the firewall rules of the build.
+	FirewallsReadyCondition clusterv1.ConditionType = "FirewallsReady"
+	// FirewallsReconciliationFailedReason used when the firewall rules can't be reconciled.
+	FirewallsReconciliationFailedReason = "FirewallsReconciliationFailed"
+	// FirewallsCreatingReason used while the firewall rules are being created.
+	FirewallsCreatingReason = "FirewallsCreating"
+	// IAPFirewallRuleMissingReason used when the host project of a shared VPC has no firewall rule allowing
+	// IAP TCP forwarding to reach the builder instance.
+	IAPFirewallRuleMissingReason = "IAPFirewallRuleMissing"
+
+	// SubnetsReadyCondition reports on the reconciliation of the subnets of the build.
+	SubnetsReadyCondition clusterv1.ConditionType = "SubnetsReady"
+	// SubnetsReconciliationFailedReason used when the subnets can't be reconciled.
+	SubnetsReconciliationFailedReason = "SubnetsReconciliationFailed"
+	// SubnetsCreatingReason used while the subnets are being created.
+	SubnetsCreatingReason = "SubnetsCreating"
+
+	// InstanceProvisionedCondition reports on the creation of the builder instance.
+	InstanceProvisionedCondition clusterv1.ConditionType = "InstanceProvisioned"
+	// InstanceProvisioningFailedReason used when the builder instance can't be created.
+	InstanceProvisioningFailedReason = "InstanceProvisioningFailed"
+	// InstancePreemptedReason used when the builder instance was preempted and is being recreated.
+	InstancePreemptedReason = "InstancePreempted"
+	// InstanceCreatingReason used while the builder instance is being created.
+	InstanceCreatingReason = "InstanceCreating"
+	// ZoneResourcesExhaustedReason used when the zone had not enough resources and the builder instance
+	// is created in the next zone.
+	ZoneResourcesExhaustedReason = "ZoneResourcesExhausted"
+
+	// MachineReadyCondition reports whether the builder instance is handed over to the provisioners.
+	MachineReadyCondition clusterv1.ConditionType = "MachineReady"
+	// WaitingForInstanceReadyReason used when the builder instance did not pass the readiness gate yet.
+	WaitingForInstanceReadyReason = "WaitingForInstanceReady"
+
+	// ImageCreatedCondition reports on the creation of the artifact of the build.
+	ImageCreatedCondition clusterv1.ConditionType = "ImageCreated"
+	// WaitingForProvisionerReason used when the provisioners did not complete yet.
+	WaitingForProvisionerReason = "WaitingForProvisioner"
+	// ImageCreationInProgressReason used while the artifact, its copies or its exports are being created.
+	ImageCreationInProgressReason = "ImageCreationInProgress"
+	// ImageCreationFailedReason used when the artifact can't be created.
+	ImageCreationFailedReason = "ImageCreationFailed"
+
+	// ImageRetentionAppliedCondition reports on the retention policy applied to the image family after the build.
+	ImageRetentionAppliedCondition clusterv1.ConditionType = "ImageRetentionApplied"
+	// ImageRetentionInProgressReason used while the older images of the family are being deprecated or deleted.
+	ImageRetentionInProgressReason = "ImageRetentionInProgress"
+	// ImageRetentionFailedReason used when the retention policy could not be fully applied.
+	ImageRetentionFailedReason = "ImageRetentionFailed"
+
+	// CleanedUpCondition reports on the cleanup of the infrastructure of the build.
+	CleanedUpCondition clusterv1.ConditionType = "CleanedUp"
+	// CleanUpFailedReason used when the infrastructure of the build can't be cleaned up.
+	CleanUpFailedReason = "CleanUpFailed"
+)
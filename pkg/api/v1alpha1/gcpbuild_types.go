@@ -32,6 +32,35 @@ const (
 	GCPBuildKind string = "GCPBuild"
 )
 
+const (
+	// PreemptionRetriesExceededReason is the failure reason used when the builder instance was
+	// preempted more often than allowed by MaxPreemptionRetries.
+	PreemptionRetriesExceededReason = "PreemptionRetriesExceeded"
+
+	// InstanceReadinessTimeoutReason is the failure reason used when the builder instance did not become
+	// ready within the readiness timeout.
+	InstanceReadinessTimeoutReason = "InstanceReadinessTimeout"
+
+	// BuildTimeoutReason is the failure reason used when the build did not complete within its timeout.
+	BuildTimeoutReason = "BuildTimeout"
+
+	// InvalidArgumentReason is the failure reason used when GCP rejects a request as invalid,
+	// e.g. because of an invalid machine type.
+	InvalidArgumentReason = "InvalidArgument"
+
+	// PermissionDeniedReason is the failure reason used when the credentials of the controller lack
+	// a permission, or an API is not enabled in the project.
+	PermissionDeniedReason = "PermissionDenied"
+
+	// ResourceNotFoundReason is the failure reason used when a resource the build depends on does not exist,
+	// e.g. the source image.
+	ResourceNotFoundReason = "ResourceNotFound"
+
+	// NameConflictReason is the failure reason used when a resource the build creates already exists
+	// and is not owned by the build, e.g. an image of the same name.
+	NameConflictReason = "NameConflict"
+)
+
 // DiskType is a type to use to define with disk type will be used.
 type DiskType string
 
@@ -42,6 +71,20 @@ const (
 	PdSsdDiskType DiskType = "pd-ssd"
 	// LocalSsdDiskType defines the name for the local ssd disk.
 	LocalSsdDiskType DiskType = "local-ssd"
+	// PdBalancedDiskType defines the name for the balanced persistent disk.
+	PdBalancedDiskType DiskType = "pd-balanced"
+	// HyperdiskBalancedDiskType defines the name for the balanced hyperdisk.
+	HyperdiskBalancedDiskType DiskType = "hyperdisk-balanced"
+)
+
+// DiskInterface is the interface used to attach a disk.
+type DiskInterface string
+
+const (
+	// SCSIDiskInterface attaches the disk using SCSI.
+	SCSIDiskInterface DiskInterface = "SCSI"
+	// NVMEDiskInterface attaches the disk using NVMe.
+	NVMEDiskInterface DiskInterface = "NVME"
 )
 
 // AttachedDiskSpec degined GCP machine disk.
@@ -63,6 +106,23 @@ type AttachedDiskSpec struct {
 	// EncryptionKey defines the KMS key to be used to encrypt the disk.
 	// +optional
 	EncryptionKey *CustomerEncryptionKey `json:"encryptionKey,omitempty"`
+	// DeviceName is the name exposed to the guest OS for this disk. The disk is then available
+	// under /dev/disk/by-id/google-<deviceName>, which lets provisioners find it.
+	// If not specified, GCP assigns a name in the form persistent-disk-N.
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DeviceName *string `json:"deviceName,omitempty"`
+	// Interface is the disk interface to use for attaching this disk.
+	// Defaults to "NVME" for "local-ssd" disks and to the platform default otherwise.
+	// +kubebuilder:validation:Enum=SCSI;NVME
+	// +optional
+	Interface *DiskInterface `json:"interface,omitempty"`
+	// AutoDelete specifies whether the disk is deleted together with the builder instance.
+	// "local-ssd" disks are always deleted with the instance.
+	// Defaults to true.
+	// +optional
+	AutoDelete *bool `json:"autoDelete,omitempty"`
 }
 
 // CustomerEncryptionKey supports both Customer-Managed or Customer-Supplied encryption keys .
@@ -129,9 +189,18 @@ type SuppliedKey struct {
 	// Gets the RSA public key certificate provided by Google at: https://cloud-certs.storage.googleapis.com/google-cloud-csek-ingress.pem
 	// +optional
 	RSAEncryptedKey []byte `json:"rsaEncryptedKey,omitempty"`
+	// RawKeySecretRef references a key of a Secret in the namespace of the GCPBuild holding the
+	// RFC 4648 base64 encoded raw key, so that it doesn't need to be inlined in the spec.
+	// +optional
+	RawKeySecretRef *corev1.SecretKeySelector `json:"rawKeySecretRef,omitempty"`
+	// RSAEncryptedKeySecretRef references a key of a Secret in the namespace of the GCPBuild holding the
+	// RFC 4648 base64 encoded RSA-wrapped key, so that it doesn't need to be inlined in the spec.
+	// +optional
+	RSAEncryptedKeySecretRef *corev1.SecretKeySelector `json:"rsaEncryptedKeySecretRef,omitempty"`
 }
 
 // GCPBuildSpec defines the desired state of GCPBuild
+// +kubebuilder:validation:XValidation:rule="!has(self.failureDomains) || self.failureDomains.all(zone, zone.startsWith(self.region + '-'))",message="failureDomains must be zones of the region"
 type GCPBuildSpec struct {
 	// Embedded ConnectionSpec to define default connection credentials.
 	buildv1.ConnectionSpec `json:",inline"`
@@ -140,21 +209,37 @@ type GCPBuildSpec struct {
 	Project string `json:"project"`
 
 	// The GCP Region the cluster lives in.
+	// +kubebuilder:validation:MaxLength=63
 	Region string `json:"region"`
 
-	// The GCP Region the cluster lives in.
+	// Zone is the GCP zone the builder instance is created in, unless it has not enough resources.
+	// See FailureDomains.
 	Zone string `json:"zone"`
 
 	// InstanceType is the type of instance to create. Example: n1.standard-2
 	InstanceType string `json:"instanceType"`
 
+	// Connection defines how the controller and the provisioners access the builder instance.
+	// +optional
+	Connection *GCPConnectionSpec `json:"connection,omitempty"`
+
+	// OSType is the operating system of the source image.
+	// For Windows, a password is generated through the windows-keys metadata and WinRM connection details
+	// are added to the credentials Secret, and the instance is generalized with GCESysprep before the capture.
+	// Defaults to "Linux".
+	// +kubebuilder:validation:Enum=Linux;Windows
+	// +optional
+	OSType *OSType `json:"osType,omitempty"`
+
 	// NetworkSpec encapsulates all things related to GCP network.
 	// +optional
 	Network NetworkSpec `json:"network"`
 
-	// FailureDomains is an optional field which is used to assign selected availability zones to a cluster
-	// FailureDomains if empty, defaults to all the zones in the selected region and if specified would override
-	// the default zones.
+	// FailureDomains are the zones the builder instance falls back to, in order, when the zone it is created in
+	// has not enough resources, e.g. for the instance type. If empty, defaults to all the zones in the selected region.
+	// The zones must be in the region.
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MaxLength=63
 	// +optional
 	FailureDomains []string `json:"failureDomains,omitempty"`
 
@@ -172,15 +257,29 @@ type GCPBuildSpec struct {
 	// +optional
 	Bootstrap clusterv1.Bootstrap `json:"bootstrap,omitempty"`
 
-	// ImageFamily is the full reference to a valid image family to be used for this machine.
+	// ImageFamily is the full reference to a valid image family to be used for this machine,
+	// e.g. projects/ubuntu-os-cloud/global/images/family/ubuntu-2204-lts. A bare family name refers to
+	// a family of the build project. The family is resolved to its latest image once per build.
 	// +optional
 	ImageFamily *string `json:"imageFamily,omitempty"`
 
-	// Image is the full reference to a valid image to be used for this machine.
-	// Takes precedence over ImageFamily.
+	// Image is the full reference to a valid image to be used for this machine,
+	// e.g. projects/ubuntu-os-cloud/global/images/ubuntu-2204-jammy-v20240904.
+	// Takes precedence over ImageFamily, SourceBuildRef and SourceImageFilter.
+	// One of Image, ImageFamily, SourceBuildRef or SourceImageFilter is required.
 	// +optional
 	Image *string `json:"image,omitempty"`
 
+	// SourceBuildRef is a reference to another GCPBuild in the same namespace, whose image is used for this machine.
+	// The build waits until the referenced GCPBuild is ready. Image and ImageFamily take precedence over SourceBuildRef.
+	// +optional
+	SourceBuildRef *corev1.LocalObjectReference `json:"sourceBuildRef,omitempty"`
+
+	// SourceImageFilter selects the newest ready image matching the filter as the image to be used for this machine.
+	// Image, ImageFamily and SourceBuildRef take precedence over SourceImageFilter.
+	// +optional
+	SourceImageFilter *SourceImageFilter `json:"sourceImageFilter,omitempty"`
+
 	// AdditionalLabels is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// GCP provider. If both the GcpBuild and the GCPMachine specify the same tag name with different values, the
 	// GCPMachine's value takes precedence.
@@ -200,6 +299,7 @@ type GCPBuildSpec struct {
 
 	// PublicIP specifies whether the instance should get a public IP.
 	// Set this to true if you don't have a NAT instances or Cloud Nat setup.
+	// Builders connected with the InternalIP or IAPTunnel strategy don't need a public IP.
 	// +optional
 	PublicIP *bool `json:"publicIP,omitempty"`
 
@@ -224,6 +324,16 @@ type GCPBuildSpec struct {
 	// +optional
 	RootDeviceType *DiskType `json:"rootDeviceType,omitempty"`
 
+	// RootDeviceEncryptionKey defines the customer-managed or customer-supplied key used to
+	// encrypt the boot disk of the builder instance.
+	// +optional
+	RootDeviceEncryptionKey *CustomerEncryptionKey `json:"rootDeviceEncryptionKey,omitempty"`
+
+	// ImageEncryptionKey defines the customer-managed or customer-supplied key used to
+	// encrypt the produced image.
+	// +optional
+	ImageEncryptionKey *CustomerEncryptionKey `json:"imageEncryptionKey,omitempty"`
+
 	// AdditionalDisks are optional non-boot attached disks.
 	// +optional
 	AdditionalDisks []AttachedDiskSpec `json:"additionalDisks,omitempty"`
@@ -237,12 +347,290 @@ type GCPBuildSpec struct {
 	// +optional
 	Preemptible bool `json:"preemptible,omitempty"`
 
+	// ProvisioningModel defines if instance is spot.
+	// If set to "Standard" while preemptible is true, then the VM will be of type "Preemptible".
+	// If "Spot", VM type is "Spot".
+	// When unspecified, defaults to "Standard".
+	// +kubebuilder:validation:Enum=Standard;Spot
+	// +optional
+	ProvisioningModel *ProvisioningModel `json:"provisioningModel,omitempty"`
+
+	// InstanceTerminationAction is the action GCP takes when a Spot instance is preempted.
+	// Only used when ProvisioningModel is "Spot". Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Stop;Delete
+	// +optional
+	InstanceTerminationAction *InstanceTerminationAction `json:"instanceTerminationAction,omitempty"`
+
+	// MaxPreemptionRetries is the number of times a preempted or externally terminated builder
+	// instance is recreated before the build is marked as failed.
+	// Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPreemptionRetries *int32 `json:"maxPreemptionRetries,omitempty"`
+
+	// Timeout is the maximum duration of the build, counted from the creation of its infrastructure,
+	// e.g. once the source build is ready. Past the deadline the build fails and its infrastructure is cleaned up.
+	// The builder instance also gets a GCP-side maximum run duration with the Delete termination action,
+	// overriding instanceTerminationAction, so that it does not outlive the deadline if the controller is down.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Readiness defines when the builder instance is considered ready to accept connections.
+	// +optional
+	Readiness *ReadinessSpec `json:"readiness,omitempty"`
+
+	// ArtifactType is the kind of artifact produced by the build.
+	// Image captures the boot disk as an image, MachineImage captures the whole instance including
+	// the additional disks and the instance properties, Snapshot captures the boot disk as a snapshot.
+	// Copies, exports, image users and retention only apply to images.
+	// Defaults to "Image".
+	// +kubebuilder:validation:Enum=Image;MachineImage;Snapshot
+	// +optional
+	ArtifactType *ArtifactType `json:"artifactType,omitempty"`
+
+	// Output defines the image produced by the build.
+	// +optional
+	Output *OutputSpec `json:"output,omitempty"`
+
+	// ArtifactDeletionPolicy defines what happens to the produced image when the GCPBuild is deleted.
+	// Defaults to "Retain".
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	ArtifactDeletionPolicy *ArtifactDeletionPolicy `json:"artifactDeletionPolicy,omitempty"`
+
+	// ImageUsers is a list of principals granted roles/compute.imageUser on the produced image,
+	// so that it can be used from other projects. Principals are in the IAM member format,
+	// e.g. user:alice@example.com, group:admins@example.com, serviceAccount:sa@project.iam.gserviceaccount.com
+	// or domain:example.com.
+	// +kubebuilder:validation:items:Pattern=`^(user|group|serviceAccount|domain):.+$`
+	// +optional
+	ImageUsers []string `json:"imageUsers,omitempty"`
+
+	// Security defines the Shielded VM and Confidential VM settings of the builder instance.
+	// The matching guest OS features are set on the produced image.
+	// +optional
+	Security *SecuritySpec `json:"security,omitempty"`
+
 	// CredentialsRef is a reference to a Secret that contains the credentials to use for provisioning this cluster. If not
 	// supplied then the credentials of the controller will be used.
 	// +optional
 	CredentialsRef *corev1.SecretReference `json:"credentialsRef,omitempty"`
 }
 
+// ArtifactDeletionPolicy defines what happens to the produced image when the GCPBuild is deleted.
+type ArtifactDeletionPolicy string
+
+const (
+	// ArtifactDeletionPolicyRetain keeps the produced image when the GCPBuild is deleted.
+	ArtifactDeletionPolicyRetain ArtifactDeletionPolicy = "Retain"
+	// ArtifactDeletionPolicyDelete deletes the produced image together with the GCPBuild.
+	ArtifactDeletionPolicyDelete ArtifactDeletionPolicy = "Delete"
+)
+
+// OutputSpec defines the image produced by a GCPBuild.
+type OutputSpec struct {
+	// NameTemplate is a Go template used to name the produced image. The name is rendered once
+	// per build, so every build produces a new image and previous images are kept.
+	// The following fields are available:
+	// - .BuildName: the name of the build
+	// - .Timestamp: the UTC time the image name was rendered, formatted as 20060102150405
+	// - .UID: the first 8 characters of the GCPBuild UID
+	// - .SpecHash: the first 8 characters of the SHA-256 hash of the GCPBuild spec
+	// - .Zone: the zone of the builder instance
+	// The rendered name is lower-cased, characters that are not allowed in image names are replaced by "-"
+	// and it is truncated to 63 characters.
+	// Defaults to "forge-{{ .BuildName }}-{{ .Timestamp }}", or to "forge-{{ .BuildName }}-{{ .Zone }}-{{ .Timestamp }}"
+	// for snapshots, following the naming of scheduled snapshots.
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+
+	// Family is the name of the image family the produced image is published into.
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Family *string `json:"family,omitempty"`
+
+	// Description is the description of the produced image.
+	// Defaults to "Custom disk image created from instance: <build name>".
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Labels is an optional set of labels to add to the produced image, in addition to the
+	// AdditionalLabels and the ownership label added by the GCP provider.
+	// +optional
+	Labels Labels `json:"labels,omitempty"`
+
+	// StorageLocations are the Cloud Storage locations, regional or multi-regional, the produced image is stored in.
+	// Defaults to the multi-region closest to the source disk.
+	// +optional
+	StorageLocations []string `json:"storageLocations,omitempty"`
+
+	// GuestOSFeatures is a list of guest OS features to enable on the produced image, in addition to the
+	// ones required by the security settings. For example: GVNIC, MULTI_IP_SUBNET, VIRTIO_SCSI_MULTIQUEUE.
+	// +optional
+	GuestOSFeatures []string `json:"guestOSFeatures,omitempty"`
+
+	// Licenses is a list of license URLs to attach to the produced image.
+	// +optional
+	Licenses []string `json:"licenses,omitempty"`
+
+	// Architecture is the CPU architecture of the produced image.
+	// Defaults to the architecture of the source disk.
+	// +kubebuilder:validation:Enum=X86_64;ARM64
+	// +optional
+	Architecture *string `json:"architecture,omitempty"`
+
+	// Exports is a list of Cloud Storage destinations the produced image is exported to once it is ready.
+	// The export runs as a Cloud Build job, the Cloud Build service account needs access to the image and the bucket.
+	// +optional
+	Exports []ImageExport `json:"exports,omitempty"`
+
+	// CopyTo is a list of projects the produced image is copied to once it is ready.
+	// +optional
+	CopyTo []ImageCopy `json:"copyTo,omitempty"`
+
+	// Retention defines what happens to the older images of the family once a new image is published.
+	// Only images created by this provider are deprecated or deleted. Requires Family.
+	// The policy is applied on a best effort basis once the image is ready: failures are reported as events and
+	// in the ImageRetentionApplied condition, they don't fail the build.
+	// +optional
+	Retention *ImageRetentionPolicy `json:"retention,omitempty"`
+}
+
+// ImageDeprecationState is the deprecation state older images of a family are moved to.
+type ImageDeprecationState string
+
+const (
+	// ImageDeprecationStateDeprecated marks older images as DEPRECATED, they can still be used.
+	ImageDeprecationStateDeprecated ImageDeprecationState = "Deprecated"
+	// ImageDeprecationStateObsolete marks older images as OBSOLETE, they can't be used to create new disks anymore.
+	ImageDeprecationStateObsolete ImageDeprecationState = "Obsolete"
+)
+
+// ImageRetentionPolicy defines how older images of an image family are deprecated and deleted.
+type ImageRetentionPolicy struct {
+	// DeprecationState is the state the older images of the family are moved to, pointing at the new image as replacement.
+	// Defaults to "Deprecated".
+	// +kubebuilder:validation:Enum=Deprecated;Obsolete
+	// +optional
+	DeprecationState *ImageDeprecationState `json:"deprecationState,omitempty"`
+
+	// KeepLast is the number of most recent images of the family to keep, including the new image.
+	// Older images are deleted.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast *int32 `json:"keepLast,omitempty"`
+
+	// MaxAge is the maximum age of the images of the family. Older images are deleted,
+	// the new image is always kept.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// SecuritySpec defines the security settings of the builder instance and the produced image.
+type SecuritySpec struct {
+	// ShieldedInstanceConfig is the Shielded VM configuration for the builder instance.
+	// +optional
+	ShieldedInstanceConfig *GCPShieldedInstanceConfig `json:"shieldedInstanceConfig,omitempty"`
+
+	// ConfidentialCompute defines whether the builder instance should have confidential compute enabled
+	// and which confidential computing technology to use.
+	// If enabled, the instance is terminated on host maintenance.
+	// +kubebuilder:validation:Enum=Enabled;Disabled;AMDEncryptedVirtualizationNestedPaging;IntelTrustedDomainExtensions
+	// +optional
+	ConfidentialCompute *ConfidentialComputePolicy `json:"confidentialCompute,omitempty"`
+}
+
+// SecureBootPolicy represents the secure boot configuration for the builder instance.
+type SecureBootPolicy string
+
+const (
+	// SecureBootPolicyEnabled enables the secure boot configuration for the builder instance.
+	SecureBootPolicyEnabled SecureBootPolicy = "Enabled"
+	// SecureBootPolicyDisabled disables the secure boot configuration for the builder instance.
+	SecureBootPolicyDisabled SecureBootPolicy = "Disabled"
+)
+
+// VirtualizedTrustedPlatformModulePolicy represents the virtualized trusted platform module configuration for the builder instance.
+type VirtualizedTrustedPlatformModulePolicy string
+
+const (
+	// VirtualizedTrustedPlatformModulePolicyEnabled enables the virtualized trusted platform module for the builder instance.
+	VirtualizedTrustedPlatformModulePolicyEnabled VirtualizedTrustedPlatformModulePolicy = "Enabled"
+	// VirtualizedTrustedPlatformModulePolicyDisabled disables the virtualized trusted platform module for the builder instance.
+	VirtualizedTrustedPlatformModulePolicyDisabled VirtualizedTrustedPlatformModulePolicy = "Disabled"
+)
+
+// IntegrityMonitoringPolicy represents the integrity monitoring configuration for the builder instance.
+type IntegrityMonitoringPolicy string
+
+const (
+	// IntegrityMonitoringPolicyEnabled enables integrity monitoring for the builder instance.
+	IntegrityMonitoringPolicyEnabled IntegrityMonitoringPolicy = "Enabled"
+	// IntegrityMonitoringPolicyDisabled disables integrity monitoring for the builder instance.
+	IntegrityMonitoringPolicyDisabled IntegrityMonitoringPolicy = "Disabled"
+)
+
+// GCPShieldedInstanceConfig describes the shielded VM configuration of the instance on GCP.
+// Shielded VM configuration allow users to enable and disable Secure Boot, vTPM, and Integrity Monitoring.
+type GCPShieldedInstanceConfig struct {
+	// SecureBoot Defines whether the instance should have secure boot enabled.
+	// Secure Boot verify the digital signature of all boot components, and halting the boot process if signature verification fails.
+	// If omitted, the platform chooses a default, which is subject to change over time, currently that default is Disabled.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	SecureBoot SecureBootPolicy `json:"secureBoot,omitempty"`
+
+	// VirtualizedTrustedPlatformModule enable virtualized trusted platform module measurements to create a known good boot integrity policy baseline.
+	// The integrity policy baseline is used for comparison with measurements from subsequent VM boots to determine if anything has changed.
+	// If omitted, the platform chooses a default, which is subject to change over time, currently that default is Enabled.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	VirtualizedTrustedPlatformModule VirtualizedTrustedPlatformModulePolicy `json:"virtualizedTrustedPlatformModule,omitempty"`
+
+	// IntegrityMonitoring determines whether the instance should have integrity monitoring that verify the runtime boot integrity.
+	// Compares the most recent boot measurements to the integrity policy baseline and return
+	// a pair of pass/fail results depending on whether they match or not.
+	// If omitted, the platform chooses a default, which is subject to change over time, currently that default is Enabled.
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	// +optional
+	IntegrityMonitoring IntegrityMonitoringPolicy `json:"integrityMonitoring,omitempty"`
+}
+
+// ConfidentialComputePolicy represents the confidential compute configuration for the builder instance.
+type ConfidentialComputePolicy string
+
+const (
+	// ConfidentialComputePolicyEnabled enables confidential compute using AMD SEV.
+	ConfidentialComputePolicyEnabled ConfidentialComputePolicy = "Enabled"
+	// ConfidentialComputePolicyDisabled disables confidential compute for the builder instance.
+	ConfidentialComputePolicyDisabled ConfidentialComputePolicy = "Disabled"
+	// ConfidentialComputePolicySEVSNP enables confidential compute using AMD SEV-SNP.
+	ConfidentialComputePolicySEVSNP ConfidentialComputePolicy = "AMDEncryptedVirtualizationNestedPaging"
+	// ConfidentialComputePolicyTDX enables confidential compute using Intel TDX.
+	ConfidentialComputePolicyTDX ConfidentialComputePolicy = "IntelTrustedDomainExtensions"
+)
+
+// ProvisioningModel is a type for Spot VM enablement.
+type ProvisioningModel string
+
+const (
+	// ProvisioningModelStandard specifies the VM type to NOT be Spot.
+	ProvisioningModelStandard ProvisioningModel = "Standard"
+	// ProvisioningModelSpot specifies the VM type to be Spot.
+	ProvisioningModelSpot ProvisioningModel = "Spot"
+)
+
+// InstanceTerminationAction is the action taken when a Spot instance is preempted.
+type InstanceTerminationAction string
+
+const (
+	// InstanceTerminationActionStop stops the instance when it is preempted.
+	InstanceTerminationActionStop InstanceTerminationAction = "Stop"
+	// InstanceTerminationActionDelete deletes the instance when it is preempted.
+	InstanceTerminationActionDelete InstanceTerminationAction = "Delete"
+)
+
 // ServiceAccount describes compute.serviceAccount.
 type ServiceAccount struct {
 	// Email: Email address of the service account.
@@ -289,6 +677,63 @@ type GCPBuildStatus struct {
 	// +optional
 	ArtifactRef *string `json:"artifactRef,omitempty"`
 
+	// Artifact is a typed reference to the artifact produced by the build.
+	// +optional
+	Artifact *ArtifactReference `json:"artifact,omitempty"`
+
+	// Exports describes the exports of the produced image to Cloud Storage.
+	// +optional
+	Exports []ImageExportStatus `json:"exports,omitempty"`
+
+	// Copies describes the copies of the produced image in other projects.
+	// +optional
+	Copies []ImageCopyStatus `json:"copies,omitempty"`
+
+	// SourceImage is the image the builder instance is created from, resolved once per build.
+	// +optional
+	SourceImage *SourceImageStatus `json:"sourceImage,omitempty"`
+
+	// Image describes the image produced by the build.
+	// +optional
+	Image *ImageStatus `json:"image,omitempty"`
+
+	// StartTime is the time the build started, the timeout is counted from.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Preemptions is the number of times the builder instance was preempted or terminated
+	// externally and had to be recreated.
+	// +optional
+	Preemptions int32 `json:"preemptions,omitempty"`
+
+	// Zone is the zone the builder instance is created in, when it differs from spec.zone because
+	// that zone had not enough resources.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// ExhaustedZones are the zones that had not enough resources to create the builder instance.
+	// They are cleared once the instance exists.
+	// +optional
+	ExhaustedZones []string `json:"exhaustedZones,omitempty"`
+
+	// OSLogin describes the OS Login profile used to access the builder instance.
+	// +optional
+	OSLogin *OSLoginStatus `json:"osLogin,omitempty"`
+
+	// SysprepStarted is true once GCESysprep has been started on a Windows builder instance.
+	// +optional
+	SysprepStarted bool `json:"sysprepStarted,omitempty"`
+
+	// Operations are the GCP operations started by the controller that are not done yet.
+	// +listType=map
+	// +listMapKey=target
+	// +optional
+	Operations []OperationStatus `json:"operations,omitempty"`
+
+	// SerialOutput describes the capture of the serial port output of the builder instance.
+	// +optional
+	SerialOutput *SerialOutputStatus `json:"serialOutput,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -332,6 +777,311 @@ type GCPBuildStatus struct {
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// SourceImageFilter selects an image by query. Deprecated images are ignored.
+type SourceImageFilter struct {
+	// Project is the project the image is searched in. Defaults to the build project.
+	// +optional
+	Project *string `json:"project,omitempty"`
+
+	// Labels is a set of labels the image must have.
+	// +optional
+	Labels Labels `json:"labels,omitempty"`
+
+	// NameRegex is a RE2 regular expression the whole image name must match.
+	// +optional
+	NameRegex *string `json:"nameRegex,omitempty"`
+
+	// Architecture is the CPU architecture of the image.
+	// +kubebuilder:validation:Enum=X86_64;ARM64
+	// +optional
+	Architecture *string `json:"architecture,omitempty"`
+}
+
+// SourceImageStatus describes the image the builder instance is created from.
+type SourceImageStatus struct {
+	// SelfLink is the full URL of the source image.
+	SelfLink string `json:"selfLink"`
+
+	// ID is the unique identifier of the source image.
+	ID string `json:"id"`
+}
+
+// ReadinessSpec defines when a builder instance is considered ready to accept connections.
+// The instance must be RUNNING and, if set, expose the guest attribute.
+type ReadinessSpec struct {
+	// GuestAttribute is the query path of a guest attribute that must exist before the instance is ready,
+	// e.g. "forge/ready" written by a startup script, or "hostkeys/" which the guest agent fills once
+	// the SSH host keys are generated. A path ending with "/" waits for any key of the namespace.
+	// Guest attributes are enabled on the instance when set.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-]+/([a-zA-Z0-9-]+)?$`
+	// +optional
+	GuestAttribute *string `json:"guestAttribute,omitempty"`
+
+	// Timeout is the time the instance has to become ready after its creation, the build fails otherwise.
+	// Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// OperationStatus describes a running GCP operation.
+type OperationStatus struct {
+	// Target identifies the action on a resource the operation runs, e.g. "insert instances/forge-build".
+	Target string `json:"target"`
+
+	// Name is the name of the operation.
+	Name string `json:"name"`
+
+	// Project is the project of the operation, defaults to the project of the build.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Zone is the zone of a zonal operation.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// Region is the region of a regional operation, both Zone and Region are empty for a global operation.
+	// +optional
+	Region string `json:"region,omitempty"`
+}
+
+// SerialOutputStatus describes the capture of the serial port output of a builder instance.
+type SerialOutputStatus struct {
+	// ConfigMapRef references the ConfigMap holding the tail of the serial port output.
+	ConfigMapRef corev1.LocalObjectReference `json:"configMapRef"`
+
+	// Next is the offset of the serial port output to read from on the next capture.
+	// +optional
+	Next int64 `json:"next,omitempty"`
+
+	// LastCaptureTime is the time the serial port output was last captured.
+	// +optional
+	LastCaptureTime *metav1.Time `json:"lastCaptureTime,omitempty"`
+}
+
+// ConnectionMode describes how SSH keys are authorized on a builder instance.
+type ConnectionMode string
+
+const (
+	// MetadataConnectionMode authorizes the SSH key through the ssh-keys instance metadata.
+	MetadataConnectionMode = ConnectionMode("Metadata")
+	// OSLoginConnectionMode authorizes the SSH key through OS Login, for the service account of the controller.
+	OSLoginConnectionMode = ConnectionMode("OSLogin")
+)
+
+// GCPConnectionSpec defines how the builder instance is accessed.
+type GCPConnectionSpec struct {
+	// Mode defines how the SSH key is authorized on the builder instance.
+	// With OSLogin, the key is imported in the OS Login profile of the service account of the controller, which
+	// needs roles/compute.osAdminLogin on the project, and the POSIX username of the profile is used to connect.
+	// The key is removed once the build is cleaned up.
+	// Defaults to "Metadata".
+	// +kubebuilder:validation:Enum=Metadata;OSLogin
+	// +optional
+	Mode *ConnectionMode `json:"mode,omitempty"`
+
+	// Strategy defines the address the builder instance is reached at.
+	// With PublicIP, the instance gets a public IP which is published in the credentials Secret.
+	// With InternalIP, the internal IP of the instance is published, the controller and the provisioners need
+	// to run in the network of the instance or one peered with it.
+	// With IAPTunnel, the credentials Secret carries the project, zone, instance and port to open an IAP TCP
+	// forwarding tunnel to, and a firewall rule allowing ingress from the IAP range 35.235.240.0/20 is created.
+	// With a shared VPC, the rule must already exist in the host project, the build fails otherwise.
+	// Defaults to "PublicIP" if publicIP is true, "InternalIP" otherwise.
+	// +kubebuilder:validation:Enum=PublicIP;InternalIP;IAPTunnel
+	// +optional
+	Strategy *ConnectionStrategy `json:"strategy,omitempty"`
+}
+
+// ConnectionStrategy describes the address a builder instance is reached at.
+type ConnectionStrategy string
+
+const (
+	// PublicIPConnectionStrategy connects to the public IP of the builder instance.
+	PublicIPConnectionStrategy = ConnectionStrategy("PublicIP")
+	// InternalIPConnectionStrategy connects to the internal IP of the builder instance.
+	InternalIPConnectionStrategy = ConnectionStrategy("InternalIP")
+	// IAPTunnelConnectionStrategy connects to the builder instance through an IAP TCP forwarding tunnel.
+	IAPTunnelConnectionStrategy = ConnectionStrategy("IAPTunnel")
+)
+
+// OSLoginStatus describes the OS Login profile used to access the builder instance.
+type OSLoginStatus struct {
+	// ServiceAccount is the service account the SSH key is imported for.
+	ServiceAccount string `json:"serviceAccount"`
+
+	// Username is the POSIX username of the OS Login profile.
+	Username string `json:"username"`
+
+	// Fingerprint is the fingerprint of the imported SSH key.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// OSType describes the operating system of a builder instance.
+type OSType string
+
+const (
+	// LinuxOSType is a Linux builder instance, connected to with SSH.
+	LinuxOSType = OSType("Linux")
+	// WindowsOSType is a Windows builder instance, connected to with WinRM.
+	WindowsOSType = OSType("Windows")
+)
+
+// ArtifactType describes the kind of artifact produced by a build.
+type ArtifactType string
+
+const (
+	// ImageArtifactType captures the boot disk of the instance as an image.
+	ImageArtifactType = ArtifactType("Image")
+	// MachineImageArtifactType captures the whole instance as a machine image.
+	MachineImageArtifactType = ArtifactType("MachineImage")
+	// SnapshotArtifactType captures the boot disk of the instance as a snapshot.
+	SnapshotArtifactType = ArtifactType("Snapshot")
+)
+
+// ArtifactReference is a typed reference to the artifact produced by a build.
+type ArtifactReference struct {
+	// Type is the kind of the artifact.
+	Type ArtifactType `json:"type"`
+
+	// Name is the name of the artifact.
+	Name string `json:"name"`
+
+	// SelfLink is the full URL of the artifact, set once the artifact is ready.
+	// +optional
+	SelfLink *string `json:"selfLink,omitempty"`
+}
+
+// ImageCopy describes a copy of the produced image in another project.
+type ImageCopy struct {
+	// Project is the project the image is copied to.
+	Project string `json:"project"`
+
+	// Name is the name of the copy. Defaults to the name of the produced image.
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Family is the image family the copy is published into.
+	// +optional
+	Family *string `json:"family,omitempty"`
+
+	// CredentialsRef is a reference to a Secret that contains the credentials to use for creating the copy.
+	// Defaults to the credentials used for the build.
+	// +optional
+	CredentialsRef *corev1.SecretReference `json:"credentialsRef,omitempty"`
+}
+
+// ImageCopyStatus describes a copy of the produced image in another project.
+type ImageCopyStatus struct {
+	// Project is the project the image is copied to.
+	Project string `json:"project"`
+
+	// Name is the name of the copy.
+	Name string `json:"name"`
+
+	// SelfLink is the full URL of the copy, set once the copy is ready.
+	// +optional
+	SelfLink *string `json:"selfLink,omitempty"`
+
+	// Ready is true once the copy is available.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// FailureMessage explains why the copy failed.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// ImageExportFormat is the file format of an exported image.
+type ImageExportFormat string
+
+const (
+	// RawImageExportFormat exports the image as a disk.raw file packed in a tar.gz archive.
+	RawImageExportFormat = ImageExportFormat("raw")
+	// VMDKImageExportFormat exports the image as a VMDK file.
+	VMDKImageExportFormat = ImageExportFormat("vmdk")
+	// VHDXImageExportFormat exports the image as a VHDX file.
+	VHDXImageExportFormat = ImageExportFormat("vhdx")
+)
+
+// ImageExport describes a Cloud Storage destination the produced image is exported to.
+type ImageExport struct {
+	// Destination is the Cloud Storage location the image is exported to, in the form gs://bucket/path/.
+	// The exported object is named after the produced image, with the extension of the format.
+	// +kubebuilder:validation:Pattern=`^gs://[a-z0-9][a-z0-9._-]*[a-z0-9](/.*)?$`
+	Destination string `json:"destination"`
+
+	// Format is the file format of the exported image.
+	// +kubebuilder:validation:Enum=raw;vmdk;vhdx
+	// +kubebuilder:default=raw
+	// +optional
+	Format ImageExportFormat `json:"format,omitempty"`
+}
+
+// ImageExportStatus describes the export of the produced image to Cloud Storage.
+type ImageExportStatus struct {
+	// URI is the gs:// URI of the exported object.
+	URI string `json:"uri"`
+
+	// Format is the file format of the exported object.
+	Format ImageExportFormat `json:"format"`
+
+	// BuildID is the ID of the Cloud Build job running the export.
+	// +optional
+	BuildID string `json:"buildID,omitempty"`
+
+	// Ready is true once the exported object is available.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+}
+
+// ImageStatus describes the image produced by a GCPBuild.
+type ImageStatus struct {
+	// Name is the immutable name of the produced image.
+	Name string `json:"name"`
+
+	// Family is the image family the image is published into.
+	// +optional
+	Family *string `json:"family,omitempty"`
+
+	// SelfLink is the full URL of the image, set once the image is ready.
+	// +optional
+	SelfLink *string `json:"selfLink,omitempty"`
+
+	// Labels are the labels of the image.
+	// +optional
+	Labels Labels `json:"labels,omitempty"`
+
+	// StorageLocations are the Cloud Storage locations the image is stored in.
+	// +optional
+	StorageLocations []string `json:"storageLocations,omitempty"`
+
+	// GuestOSFeatures are the guest OS features enabled on the image.
+	// +optional
+	GuestOSFeatures []string `json:"guestOSFeatures,omitempty"`
+
+	// Licenses are the licenses attached to the image.
+	// +optional
+	Licenses []string `json:"licenses,omitempty"`
+
+	// Architecture is the CPU architecture of the image.
+	// +optional
+	Architecture *string `json:"architecture,omitempty"`
+
+	// IAMBindings are the IAM bindings of the image, once the image users are granted access.
+	// +optional
+	IAMBindings []IAMBinding `json:"iamBindings,omitempty"`
+}
+
+// IAMBinding binds a list of members to a role.
+type IAMBinding struct {
+	// Role is the role bound to the members, e.g. roles/compute.imageUser.
+	Role string `json:"role"`
+
+	// Members are the principals bound to the role.
+	// +optional
+	Members []string `json:"members,omitempty"`
+}
+
 // Network encapsulates GCP networking resources.
 type Network struct {
 	// SelfLink is the link to the Network used for this cluster.
@@ -403,6 +1153,9 @@ type Network struct {
 // +kubebuilder:printcolumn:name="Build",type="string",JSONPath=".metadata.labels['forge\\.build/build-name']",description="Build"
 // +kubebuilder:printcolumn:name="Machine Ready",type="string",JSONPath=".status.machineReady",description="Machine Ready"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Build is ready"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",description="Reason of the phase the build is at"
+// +kubebuilder:printcolumn:name="Message",type="string",priority=1,JSONPath=".status.conditions[?(@.type=='Ready')].message",description="Message of the phase the build is at"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of GCPBuild"
 
 // GCPBuild is the Schema for the gcpbuilds API
 type GCPBuild struct {
@@ -413,6 +1166,16 @@ type GCPBuild struct {
 	Status GCPBuildStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the observations of the operational state of the GCPBuild resource.
+func (r *GCPBuild) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the GCPBuild to the predescribed clusterv1.Conditions.
+func (r *GCPBuild) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // GCPBuildList contains a list of GCPBuild
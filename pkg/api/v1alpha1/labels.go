@@ -19,7 +19,11 @@ package v1alpha1
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 )
 
 // Labels defines a map of tags.
@@ -37,15 +41,33 @@ func (in Labels) HasOwned(build string) bool {
 	return ok && ResourceLifecycle(value) == ResourceLifecycleOwned
 }
 
-// ToComputeFilter returns the string representation of the labels as a filter
-// to be used in google compute sdk calls.
-func (in Labels) ToComputeFilter() string {
-	var builder strings.Builder
-	for k, v := range in {
-		builder.WriteString(fmt.Sprintf("(labels.%s = %q) ", k, v))
+// HasProviderOwned returns true if the tags contains a tag that marks the resource as owned by any build
+// from the perspective of this management tooling.
+func (in Labels) HasProviderOwned() bool {
+	for key, value := range in {
+		if strings.HasPrefix(key, NameGCPProviderOwned) && ResourceLifecycle(value) == ResourceLifecycleOwned {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToComputeFilter returns the labels as a filter to be used in google compute list calls of the cloud
+// provider library. Label values are matched literally.
+func (in Labels) ToComputeFilter() *filter.F {
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fl := &filter.F{}
+	for _, k := range keys {
+		fl = fl.AndRegexp(fmt.Sprintf("labels.%s", k), regexp.QuoteMeta(in[k]))
 	}
 
-	return builder.String()
+	return fl
 }
 
 // Difference returns the difference between this map of tags and the other map of tags.
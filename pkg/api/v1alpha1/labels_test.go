@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLabelsToComputeFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels Labels
+		want   string
+	}{
+		{
+			name:   "no labels",
+			labels: nil,
+			want:   "",
+		},
+		{
+			name:   "single label",
+			labels: Labels{"os": "ubuntu"},
+			want:   "labels.os eq ubuntu",
+		},
+		{
+			name:   "labels sorted by key",
+			labels: Labels{"version": "2204", "os": "ubuntu"},
+			want:   "(labels.os eq ubuntu) (labels.version eq 2204)",
+		},
+		{
+			name:   "values matched literally",
+			labels: Labels{"release": "22.04"},
+			want:   `labels.release eq 22\.04`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.labels.ToComputeFilter().String()).To(Equal(tt.want))
+		})
+	}
+}
@@ -22,10 +22,31 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactReference) DeepCopyInto(out *ArtifactReference) {
+	*out = *in
+	if in.SelfLink != nil {
+		in, out := &in.SelfLink, &out.SelfLink
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactReference.
+func (in *ArtifactReference) DeepCopy() *ArtifactReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AttachedDiskSpec) DeepCopyInto(out *AttachedDiskSpec) {
 	*out = *in
@@ -44,6 +65,21 @@ func (in *AttachedDiskSpec) DeepCopyInto(out *AttachedDiskSpec) {
 		*out = new(CustomerEncryptionKey)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeviceName != nil {
+		in, out := &in.DeviceName, &out.DeviceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Interface != nil {
+		in, out := &in.Interface, &out.Interface
+		*out = new(DiskInterface)
+		**out = **in
+	}
+	if in.AutoDelete != nil {
+		in, out := &in.AutoDelete, &out.AutoDelete
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachedDiskSpec.
@@ -171,6 +207,16 @@ func (in *GCPBuildList) DeepCopyObject() runtime.Object {
 func (in *GCPBuildSpec) DeepCopyInto(out *GCPBuildSpec) {
 	*out = *in
 	in.ConnectionSpec.DeepCopyInto(&out.ConnectionSpec)
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(GCPConnectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OSType != nil {
+		in, out := &in.OSType, &out.OSType
+		*out = new(OSType)
+		**out = **in
+	}
 	in.Network.DeepCopyInto(&out.Network)
 	if in.FailureDomains != nil {
 		in, out := &in.FailureDomains, &out.FailureDomains
@@ -198,6 +244,16 @@ func (in *GCPBuildSpec) DeepCopyInto(out *GCPBuildSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SourceBuildRef != nil {
+		in, out := &in.SourceBuildRef, &out.SourceBuildRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SourceImageFilter != nil {
+		in, out := &in.SourceImageFilter, &out.SourceImageFilter
+		*out = new(SourceImageFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalLabels != nil {
 		in, out := &in.AdditionalLabels, &out.AdditionalLabels
 		*out = make(Labels, len(*in))
@@ -227,6 +283,16 @@ func (in *GCPBuildSpec) DeepCopyInto(out *GCPBuildSpec) {
 		*out = new(DiskType)
 		**out = **in
 	}
+	if in.RootDeviceEncryptionKey != nil {
+		in, out := &in.RootDeviceEncryptionKey, &out.RootDeviceEncryptionKey
+		*out = new(CustomerEncryptionKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageEncryptionKey != nil {
+		in, out := &in.ImageEncryptionKey, &out.ImageEncryptionKey
+		*out = new(CustomerEncryptionKey)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalDisks != nil {
 		in, out := &in.AdditionalDisks, &out.AdditionalDisks
 		*out = make([]AttachedDiskSpec, len(*in))
@@ -239,6 +305,56 @@ func (in *GCPBuildSpec) DeepCopyInto(out *GCPBuildSpec) {
 		*out = new(ServiceAccount)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProvisioningModel != nil {
+		in, out := &in.ProvisioningModel, &out.ProvisioningModel
+		*out = new(ProvisioningModel)
+		**out = **in
+	}
+	if in.InstanceTerminationAction != nil {
+		in, out := &in.InstanceTerminationAction, &out.InstanceTerminationAction
+		*out = new(InstanceTerminationAction)
+		**out = **in
+	}
+	if in.MaxPreemptionRetries != nil {
+		in, out := &in.MaxPreemptionRetries, &out.MaxPreemptionRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ReadinessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArtifactType != nil {
+		in, out := &in.ArtifactType, &out.ArtifactType
+		*out = new(ArtifactType)
+		**out = **in
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(OutputSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArtifactDeletionPolicy != nil {
+		in, out := &in.ArtifactDeletionPolicy, &out.ArtifactDeletionPolicy
+		*out = new(ArtifactDeletionPolicy)
+		**out = **in
+	}
+	if in.ImageUsers != nil {
+		in, out := &in.ImageUsers, &out.ImageUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecuritySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CredentialsRef != nil {
 		in, out := &in.CredentialsRef, &out.CredentialsRef
 		*out = new(v1.SecretReference)
@@ -270,6 +386,57 @@ func (in *GCPBuildStatus) DeepCopyInto(out *GCPBuildStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Artifact != nil {
+		in, out := &in.Artifact, &out.Artifact
+		*out = new(ArtifactReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exports != nil {
+		in, out := &in.Exports, &out.Exports
+		*out = make([]ImageExportStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Copies != nil {
+		in, out := &in.Copies, &out.Copies
+		*out = make([]ImageCopyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SourceImage != nil {
+		in, out := &in.SourceImage, &out.SourceImage
+		*out = new(SourceImageStatus)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExhaustedZones != nil {
+		in, out := &in.ExhaustedZones, &out.ExhaustedZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OSLogin != nil {
+		in, out := &in.OSLogin, &out.OSLogin
+		*out = new(OSLoginStatus)
+		**out = **in
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]OperationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SerialOutput != nil {
+		in, out := &in.SerialOutput, &out.SerialOutput
+		*out = new(SerialOutputStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(string)
@@ -299,6 +466,240 @@ func (in *GCPBuildStatus) DeepCopy() *GCPBuildStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPConnectionSpec) DeepCopyInto(out *GCPConnectionSpec) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ConnectionMode)
+		**out = **in
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(ConnectionStrategy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPConnectionSpec.
+func (in *GCPConnectionSpec) DeepCopy() *GCPConnectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPConnectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPShieldedInstanceConfig) DeepCopyInto(out *GCPShieldedInstanceConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPShieldedInstanceConfig.
+func (in *GCPShieldedInstanceConfig) DeepCopy() *GCPShieldedInstanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPShieldedInstanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMBinding) DeepCopyInto(out *IAMBinding) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMBinding.
+func (in *IAMBinding) DeepCopy() *IAMBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCopy) DeepCopyInto(out *ImageCopy) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Family != nil {
+		in, out := &in.Family, &out.Family
+		*out = new(string)
+		**out = **in
+	}
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCopy.
+func (in *ImageCopy) DeepCopy() *ImageCopy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCopy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCopyStatus) DeepCopyInto(out *ImageCopyStatus) {
+	*out = *in
+	if in.SelfLink != nil {
+		in, out := &in.SelfLink, &out.SelfLink
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCopyStatus.
+func (in *ImageCopyStatus) DeepCopy() *ImageCopyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCopyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageExport) DeepCopyInto(out *ImageExport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageExport.
+func (in *ImageExport) DeepCopy() *ImageExport {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageExportStatus) DeepCopyInto(out *ImageExportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageExportStatus.
+func (in *ImageExportStatus) DeepCopy() *ImageExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRetentionPolicy) DeepCopyInto(out *ImageRetentionPolicy) {
+	*out = *in
+	if in.DeprecationState != nil {
+		in, out := &in.DeprecationState, &out.DeprecationState
+		*out = new(ImageDeprecationState)
+		**out = **in
+	}
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRetentionPolicy.
+func (in *ImageRetentionPolicy) DeepCopy() *ImageRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+	if in.Family != nil {
+		in, out := &in.Family, &out.Family
+		*out = new(string)
+		**out = **in
+	}
+	if in.SelfLink != nil {
+		in, out := &in.SelfLink, &out.SelfLink
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StorageLocations != nil {
+		in, out := &in.StorageLocations, &out.StorageLocations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GuestOSFeatures != nil {
+		in, out := &in.GuestOSFeatures, &out.GuestOSFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Licenses != nil {
+		in, out := &in.Licenses, &out.Licenses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Architecture != nil {
+		in, out := &in.Architecture, &out.Architecture
+		*out = new(string)
+		**out = **in
+	}
+	if in.IAMBindings != nil {
+		in, out := &in.IAMBindings, &out.IAMBindings
+		*out = make([]IAMBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Labels) DeepCopyInto(out *Labels) {
 	{
@@ -536,6 +937,180 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSLoginStatus) DeepCopyInto(out *OSLoginStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSLoginStatus.
+func (in *OSLoginStatus) DeepCopy() *OSLoginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSLoginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationStatus) DeepCopyInto(out *OperationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationStatus.
+func (in *OperationStatus) DeepCopy() *OperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputSpec) DeepCopyInto(out *OutputSpec) {
+	*out = *in
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+	if in.Family != nil {
+		in, out := &in.Family, &out.Family
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StorageLocations != nil {
+		in, out := &in.StorageLocations, &out.StorageLocations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GuestOSFeatures != nil {
+		in, out := &in.GuestOSFeatures, &out.GuestOSFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Licenses != nil {
+		in, out := &in.Licenses, &out.Licenses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Architecture != nil {
+		in, out := &in.Architecture, &out.Architecture
+		*out = new(string)
+		**out = **in
+	}
+	if in.Exports != nil {
+		in, out := &in.Exports, &out.Exports
+		*out = make([]ImageExport, len(*in))
+		copy(*out, *in)
+	}
+	if in.CopyTo != nil {
+		in, out := &in.CopyTo, &out.CopyTo
+		*out = make([]ImageCopy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(ImageRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputSpec.
+func (in *OutputSpec) DeepCopy() *OutputSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessSpec) DeepCopyInto(out *ReadinessSpec) {
+	*out = *in
+	if in.GuestAttribute != nil {
+		in, out := &in.GuestAttribute, &out.GuestAttribute
+		*out = new(string)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessSpec.
+func (in *ReadinessSpec) DeepCopy() *ReadinessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
+	*out = *in
+	if in.ShieldedInstanceConfig != nil {
+		in, out := &in.ShieldedInstanceConfig, &out.ShieldedInstanceConfig
+		*out = new(GCPShieldedInstanceConfig)
+		**out = **in
+	}
+	if in.ConfidentialCompute != nil {
+		in, out := &in.ConfidentialCompute, &out.ConfidentialCompute
+		*out = new(ConfidentialComputePolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecuritySpec.
+func (in *SecuritySpec) DeepCopy() *SecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SerialOutputStatus) DeepCopyInto(out *SerialOutputStatus) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+	if in.LastCaptureTime != nil {
+		in, out := &in.LastCaptureTime, &out.LastCaptureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SerialOutputStatus.
+func (in *SerialOutputStatus) DeepCopy() *SerialOutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SerialOutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccount) DeepCopyInto(out *ServiceAccount) {
 	*out = *in
@@ -556,6 +1131,58 @@ func (in *ServiceAccount) DeepCopy() *ServiceAccount {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceImageFilter) DeepCopyInto(out *SourceImageFilter) {
+	*out = *in
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NameRegex != nil {
+		in, out := &in.NameRegex, &out.NameRegex
+		*out = new(string)
+		**out = **in
+	}
+	if in.Architecture != nil {
+		in, out := &in.Architecture, &out.Architecture
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceImageFilter.
+func (in *SourceImageFilter) DeepCopy() *SourceImageFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceImageFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceImageStatus) DeepCopyInto(out *SourceImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceImageStatus.
+func (in *SourceImageStatus) DeepCopy() *SourceImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
@@ -632,6 +1259,16 @@ func (in *SuppliedKey) DeepCopyInto(out *SuppliedKey) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.RawKeySecretRef != nil {
+		in, out := &in.RawKeySecretRef, &out.RawKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RSAEncryptedKeySecretRef != nil {
+		in, out := &in.RSAEncryptedKeySecretRef, &out.RSAEncryptedKeySecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuppliedKey.
@@ -18,37 +18,165 @@ package firewalls
 
 import (
 	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
-	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
 )
 
 // Reconcile reconcile cluster firewall compoenents.
 func (s *Service) Reconcile(ctx context.Context) error {
 	if s.scope.IsSharedVpc() {
 		s.Log.V(1).Info("Shared VPC enabled. Ignore Reconciling firewall resources")
+		if s.scope.ConnectionStrategy() == infrav1.IAPTunnelConnectionStrategy {
+			if err := s.checkIAPFirewallRule(ctx); err != nil {
+				return err
+			}
+		}
+		s.scope.MarkConditionTrue(infrav1.FirewallsReadyCondition)
 		return nil
 	}
 	s.Log.Info("Reconciling firewall resources")
+	var creating []string
 	for _, spec := range s.scope.FirewallRulesSpec() {
+		// The firewall rules are created in parallel, wait for all of them before moving on.
+		insertTarget := operations.Target("insert", "firewalls", spec.Name)
+		done, err := s.operations.Poll(ctx, insertTarget)
+		if err != nil {
+			s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.FirewallsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		if !done {
+			creating = append(creating, spec.Name)
+			continue
+		}
+
 		s.Log.V(1).Info("Looking firewall", "name", spec.Name)
 		firewallKey := meta.GlobalKey(spec.Name)
 		if _, err := s.firewalls.Get(ctx, firewallKey); err != nil {
 			if !gcperrors.IsNotFound(err) {
+				s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.FirewallsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 				return err
 			}
 
 			s.Log.V(1).Info("Creating firewall", "name", spec.Name)
-			if err := s.firewalls.Insert(ctx, firewallKey, spec); err != nil {
+			op, err := s.firewallOperations.Insert(s.scope.Project(), spec).Context(ctx).Do()
+			if err == nil {
+				err = s.operations.Track(insertTarget, op)
+			}
+			if err != nil {
+				s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.FirewallsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 				return err
 			}
+			creating = append(creating, spec.Name)
 		}
 	}
 
+	if len(creating) > 0 {
+		err := errors.Wrapf(operations.ErrInProgress, "firewall rules %v are being created", creating)
+		s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.FirewallsCreatingReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return err
+	}
+
+	s.scope.MarkConditionTrue(infrav1.FirewallsReadyCondition)
 	return nil
 }
 
+// checkIAPFirewallRule checks that the host project of the shared VPC has a firewall rule allowing IAP TCP
+// forwarding to reach the builder instance, the build can't create it there.
+func (s *Service) checkIAPFirewallRule(ctx context.Context) error {
+	spec := s.scope.IAPFirewallRuleSpec()
+	rules, err := s.firewalls.List(ctx, filter.None)
+	if err != nil {
+		s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.FirewallsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return errors.Wrapf(err, "failed to list the firewall rules of host project %s", s.scope.NetworkProject())
+	}
+	for _, rule := range rules {
+		if allowsIAP(rule, spec) {
+			s.Log.V(1).Info("Found IAP firewall rule in host project", "name", rule.Name, "project", s.scope.NetworkProject())
+			return nil
+		}
+	}
+
+	err = gcperrors.NewMissingResourceError(
+		fmt.Sprintf("firewall rule of host project %s", s.scope.NetworkProject()),
+		errors.Errorf("no rule of network %s allows %s to reach port %s of the instances tagged %s, it must be created in the host project",
+			s.scope.NetworkName(), spec.SourceRanges[0], spec.Allowed[0].Ports[0], spec.TargetTags[0]),
+	)
+	s.scope.MarkConditionFalse(infrav1.FirewallsReadyCondition, infrav1.IAPFirewallRuleMissingReason, clusterv1.ConditionSeverityError, err.Error())
+	return err
+}
+
+// allowsIAP reports whether the given firewall rule allows the traffic of the given IAP firewall rule spec.
+// The service accounts of the instances are not resolved, rules targeting service accounts are trusted.
+func allowsIAP(rule, spec *compute.Firewall) bool {
+	if rule.Disabled || rule.Direction != spec.Direction || !strings.HasSuffix(rule.Network, spec.Network) {
+		return false
+	}
+	if len(rule.TargetTags) > 0 && !slices.Contains(rule.TargetTags, spec.TargetTags[0]) {
+		return false
+	}
+
+	return slices.ContainsFunc(rule.SourceRanges, func(sourceRange string) bool {
+		return containsRange(sourceRange, spec.SourceRanges[0])
+	}) && slices.ContainsFunc(rule.Allowed, func(allowed *compute.FirewallAllowed) bool {
+		return allowsPort(allowed, spec.Allowed[0].IPProtocol, spec.Allowed[0].Ports[0])
+	})
+}
+
+// containsRange reports whether the CIDR range outer contains the CIDR range inner.
+func containsRange(outer, inner string) bool {
+	outerPrefix, err := netip.ParsePrefix(outer)
+	if err != nil {
+		return false
+	}
+	innerPrefix, err := netip.ParsePrefix(inner)
+	if err != nil {
+		return false
+	}
+
+	return outerPrefix.Bits() <= innerPrefix.Bits() && outerPrefix.Contains(innerPrefix.Addr())
+}
+
+// allowsPort reports whether the allowed traffic of a firewall rule includes the given protocol and port.
+func allowsPort(allowed *compute.FirewallAllowed, protocol, port string) bool {
+	if allowed.IPProtocol != "all" && !strings.EqualFold(allowed.IPProtocol, protocol) {
+		return false
+	}
+	if len(allowed.Ports) == 0 {
+		return true
+	}
+
+	target, err := strconv.Atoi(port)
+	if err != nil {
+		return false
+	}
+	for _, ports := range allowed.Ports {
+		first, last, _ := strings.Cut(ports, "-")
+		if last == "" {
+			last = first
+		}
+		low, errLow := strconv.Atoi(first)
+		high, errHigh := strconv.Atoi(last)
+		if errLow == nil && errHigh == nil && low <= target && target <= high {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Delete delete cluster firewall compoenents.
 func (s *Service) Delete(ctx context.Context) error {
 	if s.scope.IsSharedVpc() {
@@ -56,15 +184,35 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 	s.Log.Info("Deleting firewall resources")
+	var deleting []string
 	for _, spec := range s.scope.FirewallRulesSpec() {
+		deleteTarget := operations.Target("delete", "firewalls", spec.Name)
+		done, err := s.operations.Poll(ctx, deleteTarget)
+		if err != nil {
+			return err
+		}
+		if !done {
+			deleting = append(deleting, spec.Name)
+			continue
+		}
+
 		s.Log.V(2).Info("Deleting firewall", "name", spec.Name)
-		firewallKey := meta.GlobalKey(spec.Name)
-		if err := s.firewalls.Delete(ctx, firewallKey); err != nil {
+		op, err := s.firewallOperations.Delete(s.scope.Project(), spec.Name).Context(ctx).Do()
+		if err != nil {
 			if !gcperrors.IsNotFound(err) {
 				s.Log.Error(err, "Error deleting firewall", "name", spec.Name)
 				return err
 			}
+			continue
+		}
+		if err := s.operations.Track(deleteTarget, op); err != nil {
+			return err
 		}
+		deleting = append(deleting, spec.Name)
+	}
+
+	if len(deleting) > 0 {
+		return errors.Wrapf(operations.ErrInProgress, "firewall rules %v are being deleted", deleting)
 	}
 
 	return nil
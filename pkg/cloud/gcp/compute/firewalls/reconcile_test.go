@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewalls
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestAllowsIAP(t *testing.T) {
+	spec := &compute.Firewall{
+		Name:         "allow-my-build-iap",
+		Network:      "projects/host-project/global/networks/shared",
+		Allowed:      []*compute.FirewallAllowed{{IPProtocol: "TCP", Ports: []string{"22"}}},
+		Direction:    "INGRESS",
+		SourceRanges: []string{"35.235.240.0/20"},
+		TargetTags:   []string{"my-build-forge-builder"},
+	}
+	rule := func(modify func(rule *compute.Firewall)) *compute.Firewall {
+		rule := &compute.Firewall{
+			Name:         "allow-iap",
+			Network:      "https://www.googleapis.com/compute/v1/projects/host-project/global/networks/shared",
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}},
+			Direction:    "INGRESS",
+			SourceRanges: []string{"35.235.240.0/20"},
+		}
+		if modify != nil {
+			modify(rule)
+		}
+		return rule
+	}
+
+	tests := []struct {
+		name string
+		rule *compute.Firewall
+		want bool
+	}{
+		{
+			name: "rule for all instances",
+			rule: rule(nil),
+			want: true,
+		},
+		{
+			name: "rule for the builder tag",
+			rule: rule(func(rule *compute.Firewall) { rule.TargetTags = []string{"other", "my-build-forge-builder"} }),
+			want: true,
+		},
+		{
+			name: "rule for other tags",
+			rule: rule(func(rule *compute.Firewall) { rule.TargetTags = []string{"other"} }),
+		},
+		{
+			name: "rule for a service account",
+			rule: rule(func(rule *compute.Firewall) {
+				rule.TargetServiceAccounts = []string{"builder@my-project.iam.gserviceaccount.com"}
+			}),
+			want: true,
+		},
+		{
+			name: "wider source range",
+			rule: rule(func(rule *compute.Firewall) { rule.SourceRanges = []string{"10.0.0.0/8", "35.235.0.0/16"} }),
+			want: true,
+		},
+		{
+			name: "narrower source range",
+			rule: rule(func(rule *compute.Firewall) { rule.SourceRanges = []string{"35.235.240.0/24"} }),
+		},
+		{
+			name: "port range",
+			rule: rule(func(rule *compute.Firewall) { rule.Allowed[0].Ports = []string{"80", "20-30"} }),
+			want: true,
+		},
+		{
+			name: "all ports",
+			rule: rule(func(rule *compute.Firewall) { rule.Allowed[0].Ports = nil }),
+			want: true,
+		},
+		{
+			name: "all protocols",
+			rule: rule(func(rule *compute.Firewall) { rule.Allowed = []*compute.FirewallAllowed{{IPProtocol: "all"}} }),
+			want: true,
+		},
+		{
+			name: "other port",
+			rule: rule(func(rule *compute.Firewall) { rule.Allowed[0].Ports = []string{"3389"} }),
+		},
+		{
+			name: "other protocol",
+			rule: rule(func(rule *compute.Firewall) { rule.Allowed[0].IPProtocol = "udp" }),
+		},
+		{
+			name: "other network",
+			rule: rule(func(rule *compute.Firewall) {
+				rule.Network = "https://www.googleapis.com/compute/v1/projects/host-project/global/networks/other"
+			}),
+		},
+		{
+			name: "egress rule",
+			rule: rule(func(rule *compute.Firewall) { rule.Direction = "EGRESS" }),
+		},
+		{
+			name: "disabled rule",
+			rule: rule(func(rule *compute.Firewall) { rule.Disabled = true }),
+		},
+		{
+			name: "deny rule",
+			rule: rule(func(rule *compute.Firewall) {
+				rule.Denied = []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"22"}}}
+				rule.Allowed = nil
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(allowsIAP(tt.rule, spec)).To(Equal(tt.want))
+		})
+	}
+}
@@ -20,42 +20,64 @@ import (
 	"context"
 
 	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/go-logr/logr"
 	"google.golang.org/api/compute/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
 )
 
 const ServiceName = "firewall-reconciler"
 
 type firewallsInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Firewall, error)
-	Insert(ctx context.Context, key *meta.Key, obj *compute.Firewall, options ...k8scloud.Option) error
-	Update(ctx context.Context, key *meta.Key, obj *compute.Firewall, options ...k8scloud.Option) error
-	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+	List(ctx context.Context, fl *filter.F, options ...k8scloud.Option) ([]*compute.Firewall, error)
+}
+
+type firewallOperationsInterface interface {
+	Insert(project string, firewall *compute.Firewall) *compute.FirewallsInsertCall
+	Delete(project string, firewall string) *compute.FirewallsDeleteCall
 }
 
 // Scope is an interfaces that hold used methods.
 type Scope interface {
 	cloud.BuildGetter
+	operations.Scope
 	FirewallRulesSpec() []*compute.Firewall
+	IAPFirewallRuleSpec() *compute.Firewall
+	ConnectionStrategy() infrav1.ConnectionStrategy
+	MarkConditionTrue(t clusterv1.ConditionType)
+	MarkConditionFalse(t clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string)
 }
 
 // Service implements firewalls reconciler.
 type Service struct {
-	scope     Scope
-	firewalls firewallsInterface
-	Log       logr.Logger
+	scope              Scope
+	firewalls          firewallsInterface
+	firewallOperations firewallOperationsInterface
+	operations         *operations.Tracker
+	Log                logr.Logger
 }
 
 var _ cloud.Reconciler = &Service{}
 
 // New returns Service from given scope.
 func New(scope Scope) *Service {
+	cloudScope := scope.Cloud()
+	if scope.IsSharedVpc() {
+		cloudScope = scope.NetworkCloud()
+	}
+
+	// The firewall rules of a shared VPC are managed in its host project, they are only looked up.
 	return &Service{
-		scope:     scope,
-		firewalls: scope.Cloud().Firewalls(),
-		Log:       scope.Log(ServiceName),
+		scope:              scope,
+		firewalls:          cloudScope.Firewalls(),
+		firewallOperations: compute.NewFirewallsService(scope.GetComputeService()),
+		operations:         operations.New(scope),
+		Log:                scope.Log(ServiceName),
 	}
 }
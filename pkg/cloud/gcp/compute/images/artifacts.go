@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// reconcileMachineImage ensures that a machine image is created from the given instance,
+// including its additional disks and its properties.
+func (s *Service) reconcileMachineImage(ctx context.Context, instance *compute.Instance) error {
+	spec := s.scope.MachineImageSpec(instance)
+	insertTarget := operations.Target("insert", "machineImages", spec.Name)
+	done, err := s.operations.Poll(ctx, insertTarget)
+	if err != nil || !done {
+		return err
+	}
+
+	machineImage, err := s.machineImages.Get(s.scope.Project(), spec.Name).Context(ctx).Do()
+	if err != nil {
+		if !gcperrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get machine image status: %w", err)
+		}
+
+		s.Log.Info("Creating machine image from instance", "instance", instance.Name, "machineImage", spec.Name)
+		op, err := s.machineImages.Insert(s.scope.Project(), spec).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to create machine image: %w", err)
+		}
+
+		return s.operations.Track(insertTarget, op)
+	}
+
+	// Machine images have no labels, the ones of the build are captured from its instance.
+	if !sameResource(machineImage.SourceInstance, instance.SelfLink) {
+		return gcperrors.NewNameConflictError(fmt.Sprintf("machine image %s", machineImage.Name))
+	}
+
+	switch machineImage.Status {
+	case "READY":
+	case "INVALID":
+		return fmt.Errorf("machine image %s is invalid", machineImage.Name)
+	default:
+		s.Log.V(1).Info("Machine image is not ready yet", "machineImage", machineImage.Name, "status", machineImage.Status)
+		return nil
+	}
+
+	s.scope.SetArtifactReady(machineImage.SelfLink)
+	s.Log.Info("Machine image reconciliation successful", "machineImage", machineImage.Name)
+	return nil
+}
+
+// reconcileSnapshot ensures that a snapshot is created from the instance's boot disk.
+func (s *Service) reconcileSnapshot(ctx context.Context) error {
+	spec := s.scope.SnapshotSpec()
+	insertTarget := operations.Target("insert", "snapshots", spec.Name)
+	done, err := s.operations.Poll(ctx, insertTarget)
+	if err != nil || !done {
+		return err
+	}
+
+	snapshot, err := s.snapshots.Get(s.scope.Project(), spec.Name).Context(ctx).Do()
+	if err != nil {
+		if !gcperrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get snapshot status: %w", err)
+		}
+
+		s.Log.Info("Creating snapshot from instance boot disk", "instance", s.scope.Name(), "snapshot", spec.Name)
+		op, err := s.snapshots.Insert(s.scope.Project(), spec).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+
+		return s.operations.Track(insertTarget, op)
+	}
+
+	if !infrav1.Labels(snapshot.Labels).HasOwned(s.scope.Name()) {
+		return gcperrors.NewNameConflictError(fmt.Sprintf("snapshot %s", snapshot.Name))
+	}
+
+	switch snapshot.Status {
+	case "READY":
+	case "FAILED":
+		return fmt.Errorf("snapshot %s failed", snapshot.Name)
+	default:
+		s.Log.V(1).Info("Snapshot is not ready yet", "snapshot", snapshot.Name, "status", snapshot.Status)
+		return nil
+	}
+
+	s.scope.SetArtifactReady(snapshot.SelfLink)
+	s.Log.Info("Snapshot reconciliation successful", "snapshot", snapshot.Name)
+	return nil
+}
+
+// deleteMachineImage deletes the produced machine image, it returns an in progress error until it is deleted.
+func (s *Service) deleteMachineImage(ctx context.Context, name string) error {
+	deleteTarget := operations.Target("delete", "machineImages", name)
+	done, err := s.operations.Poll(ctx, deleteTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(operations.ErrInProgress, "machine image %s is being deleted", name)
+	}
+
+	s.Log.Info("Deleting machine image", "machineImage", name)
+	op, err := s.machineImages.Delete(s.scope.Project(), name).Context(ctx).Do()
+	if err == nil {
+		if err := s.operations.Track(deleteTarget, op); err != nil {
+			return err
+		}
+
+		return errors.Wrapf(operations.ErrInProgress, "machine image %s is being deleted", name)
+	}
+	if !gcperrors.IsNotFound(err) {
+		s.Log.Error(err, "Error deleting machine image", "machineImage", name)
+		return err
+	}
+
+	return nil
+}
+
+// deleteSnapshot deletes the produced snapshot, it returns an in progress error until it is deleted.
+func (s *Service) deleteSnapshot(ctx context.Context, name string) error {
+	deleteTarget := operations.Target("delete", "snapshots", name)
+	done, err := s.operations.Poll(ctx, deleteTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(operations.ErrInProgress, "snapshot %s is being deleted", name)
+	}
+
+	s.Log.Info("Deleting snapshot", "snapshot", name)
+	op, err := s.snapshots.Delete(s.scope.Project(), name).Context(ctx).Do()
+	if err == nil {
+		if err := s.operations.Track(deleteTarget, op); err != nil {
+			return err
+		}
+
+		return errors.Wrapf(operations.ErrInProgress, "snapshot %s is being deleted", name)
+	}
+	if !gcperrors.IsNotFound(err) {
+		s.Log.Error(err, "Error deleting snapshot", "snapshot", name)
+		return err
+	}
+
+	return nil
+}
+
+// sameResource reports whether the given URLs, full or partial, refer to the same resource.
+func sameResource(a, b string) bool {
+	_, pathA, okA := strings.Cut(a, "projects/")
+	_, pathB, okB := strings.Cut(b, "projects/")
+
+	return okA && okB && pathA == pathB
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSameResource(t *testing.T) {
+	const instance = "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/build"
+
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{
+			name: "same URL",
+			a:    instance,
+			b:    instance,
+			want: true,
+		},
+		{
+			name: "partial URL",
+			a:    instance,
+			b:    "projects/my-project/zones/us-central1-a/instances/build",
+			want: true,
+		},
+		{
+			name: "other instance",
+			a:    instance,
+			b:    "projects/my-project/zones/us-central1-a/instances/other-build",
+		},
+		{
+			name: "other project",
+			a:    instance,
+			b:    "projects/other-project/zones/us-central1-a/instances/build",
+		},
+		{
+			name: "empty",
+			a:    "",
+			b:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(sameResource(tt.a, tt.b)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// reconcileCopies copies the given produced image to the target projects.
+// It returns true once all the copies are ready.
+func (s *Service) reconcileCopies(ctx context.Context, source *compute.Image) (bool, error) {
+	copied := true
+	for _, target := range s.scope.ImageCopies() {
+		targetImages, tracker, err := s.targetImages(ctx, target.CredentialsRef)
+		if err != nil {
+			return false, err
+		}
+
+		spec := s.scope.ImageCopySpec(target, source)
+		insertTarget := copyTarget("insert", target.Project, spec.Name)
+		done, err := tracker.Poll(ctx, insertTarget)
+		if err != nil {
+			err = fmt.Errorf("failed to copy image to project %s: %w", target.Project, err)
+			s.setImageCopyFailed(target.Project, spec.Name, err)
+			return false, err
+		}
+		if !done {
+			s.Log.V(1).Info("Disk image is being copied", "project", target.Project, "copy", spec.Name)
+			copied = false
+			continue
+		}
+
+		image, err := targetImages.Get(target.Project, spec.Name).Context(ctx).Do()
+		if err != nil {
+			if !gcperrors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to get image copy %s in project %s: %w", spec.Name, target.Project, err)
+			}
+
+			s.Log.Info("Copying disk image", "image", source.Name, "project", target.Project, "copy", spec.Name)
+			op, err := targetImages.Insert(target.Project, spec).Context(ctx).Do()
+			if err != nil {
+				err = fmt.Errorf("failed to copy image to project %s: %w", target.Project, err)
+				s.setImageCopyFailed(target.Project, spec.Name, err)
+				return false, err
+			}
+			s.scope.SetImageCopyStatus(infrav1.ImageCopyStatus{Project: target.Project, Name: spec.Name})
+			if err := tracker.Track(insertTarget, op); err != nil {
+				err = fmt.Errorf("failed to copy image to project %s: %w", target.Project, err)
+				s.setImageCopyFailed(target.Project, spec.Name, err)
+				return false, err
+			}
+			copied = false
+			continue
+		}
+
+		if !infrav1.Labels(image.Labels).HasOwned(s.scope.Name()) {
+			return false, gcperrors.NewNameConflictError(fmt.Sprintf("image %s in project %s", spec.Name, target.Project))
+		}
+
+		switch image.Status {
+		case "READY":
+			s.scope.SetImageCopyStatus(infrav1.ImageCopyStatus{
+				Project:  target.Project,
+				Name:     image.Name,
+				SelfLink: ptr.To(image.SelfLink),
+				Ready:    true,
+			})
+		case "FAILED":
+			err := fmt.Errorf("failed to copy image to project %s: image %s failed", target.Project, image.Name)
+			s.setImageCopyFailed(target.Project, image.Name, err)
+			return false, err
+		default:
+			s.Log.V(1).Info("Disk image copy is not ready yet", "project", target.Project, "copy", image.Name, "status", image.Status)
+			s.scope.SetImageCopyStatus(infrav1.ImageCopyStatus{Project: target.Project, Name: image.Name})
+			copied = false
+		}
+	}
+
+	return copied, nil
+}
+
+// setImageCopyFailed reports the failure of the copy in the given project in its status.
+func (s *Service) setImageCopyFailed(project, name string, err error) {
+	s.scope.SetImageCopyStatus(infrav1.ImageCopyStatus{Project: project, Name: name, FailureMessage: ptr.To(err.Error())})
+}
+
+// deleteCopies deletes the copies of the produced image, it returns an in progress error until they are deleted.
+func (s *Service) deleteCopies(ctx context.Context) error {
+	deleting := false
+	for _, status := range s.scope.ImageCopyStatuses() {
+		targetImages, tracker, err := s.targetImages(ctx, s.copyCredentials(status))
+		if err != nil {
+			return err
+		}
+
+		deleteTarget := copyTarget("delete", status.Project, status.Name)
+		done, err := tracker.Poll(ctx, deleteTarget)
+		if err != nil {
+			return fmt.Errorf("failed to delete image copy %s in project %s: %w", status.Name, status.Project, err)
+		}
+		if !done {
+			deleting = true
+			continue
+		}
+
+		s.Log.Info("Deleting image copy", "project", status.Project, "copy", status.Name)
+		op, err := targetImages.Delete(status.Project, status.Name).Context(ctx).Do()
+		if err != nil {
+			if gcperrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to delete image copy %s in project %s: %w", status.Name, status.Project, err)
+		}
+		if err := tracker.Track(deleteTarget, op); err != nil {
+			return fmt.Errorf("failed to delete image copy %s in project %s: %w", status.Name, status.Project, err)
+		}
+		deleting = true
+	}
+
+	if deleting {
+		return errors.Wrap(operations.ErrInProgress, "image copies are being deleted")
+	}
+
+	return nil
+}
+
+// copyTarget returns the target of an operation on the copy of the given name in the given project.
+func copyTarget(action, project, name string) string {
+	return operations.Target(action, fmt.Sprintf("projects/%s/images", project), name)
+}
+
+// copyCredentials returns the credentials of the copy target the given copy was made for, nil to use the
+// credentials of the build. A target without name matches every copy in its project.
+func (s *Service) copyCredentials(status infrav1.ImageCopyStatus) *corev1.SecretReference {
+	var credentialsRef *corev1.SecretReference
+	for _, target := range s.scope.ImageCopies() {
+		if target.Project != status.Project {
+			continue
+		}
+		if target.Name != nil && *target.Name == status.Name {
+			return target.CredentialsRef
+		}
+		if target.Name == nil && credentialsRef == nil {
+			credentialsRef = target.CredentialsRef
+		}
+	}
+
+	return credentialsRef
+}
+
+// targetImages returns the images client of a copy target, and the tracker of the operations on its images,
+// using the given credentials if any.
+func (s *Service) targetImages(ctx context.Context, credentialsRef *corev1.SecretReference) (imageCopyInterface, *operations.Tracker, error) {
+	computeSvc, err := s.scope.TargetComputeService(ctx, credentialsRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create compute client for image copy: %w", err)
+	}
+
+	return compute.NewImagesService(computeSvc), operations.NewForService(s.scope, computeSvc), nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+// copyScope only implements the image copies of the Scope.
+type copyScope struct {
+	Scope
+	copies []infrav1.ImageCopy
+}
+
+func (s *copyScope) ImageCopies() []infrav1.ImageCopy { return s.copies }
+
+func TestCopyCredentials(t *testing.T) {
+	teamA := &corev1.SecretReference{Name: "team-a"}
+	teamB := &corev1.SecretReference{Name: "team-b"}
+	copies := []infrav1.ImageCopy{
+		{Project: "shared", Name: ptr.To("image-a"), CredentialsRef: teamA},
+		{Project: "shared", Name: ptr.To("image-b"), CredentialsRef: teamB},
+		{Project: "team-b", CredentialsRef: teamB},
+		{Project: "team-b", Name: ptr.To("image-a"), CredentialsRef: teamA},
+		{Project: "default"},
+	}
+
+	tests := []struct {
+		name   string
+		status infrav1.ImageCopyStatus
+		want   *corev1.SecretReference
+	}{
+		{
+			name:   "targets sharing a project",
+			status: infrav1.ImageCopyStatus{Project: "shared", Name: "image-b"},
+			want:   teamB,
+		},
+		{
+			name:   "target without name",
+			status: infrav1.ImageCopyStatus{Project: "team-b", Name: "forge-my-build"},
+			want:   teamB,
+		},
+		{
+			name:   "named target preferred",
+			status: infrav1.ImageCopyStatus{Project: "team-b", Name: "image-a"},
+			want:   teamA,
+		},
+		{
+			name:   "target without credentials",
+			status: infrav1.ImageCopyStatus{Project: "default", Name: "forge-my-build"},
+		},
+		{
+			name:   "target removed from the spec",
+			status: infrav1.ImageCopyStatus{Project: "removed", Name: "forge-my-build"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &Service{scope: &copyScope{copies: copies}}
+			g.Expect(s.copyCredentials(tt.status)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestCopyTarget(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(copyTarget("insert", "other-project", "forge-build")).To(Equal("insert projects/other-project/images/forge-build"))
+	g.Expect(copyTarget("insert", "other-project", "forge-build")).NotTo(Equal(copyTarget("insert", "third-project", "forge-build")))
+}
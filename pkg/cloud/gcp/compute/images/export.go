@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/cloudbuild/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+const (
+	// exportBuilderImage is the builder used by gcloud compute images export.
+	exportBuilderImage = "gcr.io/compute-image-tools/gce_vm_image_export:release"
+	exportTimeout      = "7200s"
+	exportToolTimeout  = "7000s"
+)
+
+// reconcileExports exports the produced image to the configured Cloud Storage destinations.
+// It returns true once all the exports are available.
+func (s *Service) reconcileExports(ctx context.Context, imageName string) (bool, error) {
+	exported := true
+	for _, export := range s.scope.ImageExports() {
+		format := export.Format
+		if format == "" {
+			format = infrav1.RawImageExportFormat
+		}
+		uri := exportURI(export.Destination, imageName, format)
+
+		status := s.findExportStatus(uri)
+		if status == nil {
+			buildID, err := s.startExport(ctx, imageName, uri, format)
+			if err != nil {
+				return false, err
+			}
+			s.scope.SetImageExportStatus(infrav1.ImageExportStatus{URI: uri, Format: format, BuildID: buildID})
+			exported = false
+			continue
+		}
+		if status.Ready {
+			continue
+		}
+
+		builds, err := s.buildsService(ctx)
+		if err != nil {
+			return false, err
+		}
+		build, err := builds.Get(s.scope.Project(), status.BuildID).Context(ctx).Do()
+		if err != nil {
+			return false, fmt.Errorf("failed to get export build %s: %w", status.BuildID, err)
+		}
+
+		switch build.Status {
+		case "SUCCESS":
+			objects, err := s.objectsService(ctx)
+			if err != nil {
+				return false, err
+			}
+			bucket, object := splitURI(uri)
+			if _, err := objects.Get(bucket, object).Context(ctx).Do(); err != nil {
+				return false, fmt.Errorf("failed to get exported image %s: %w", uri, err)
+			}
+			s.Log.Info("Disk image exported", "image", imageName, "uri", uri)
+			status.Ready = true
+			s.scope.SetImageExportStatus(*status)
+		case "FAILURE", "INTERNAL_ERROR", "TIMEOUT", "CANCELLED", "EXPIRED":
+			return false, fmt.Errorf("failed to export image to %s, build %s finished with status %s: %s", uri, build.Id, build.Status, build.StatusDetail)
+		default:
+			s.Log.V(1).Info("Disk image export is in progress", "uri", uri, "build", build.Id, "status", build.Status)
+			exported = false
+		}
+	}
+
+	return exported, nil
+}
+
+// startExport creates the Cloud Build job exporting the image to the given URI and returns its ID.
+func (s *Service) startExport(ctx context.Context, imageName, uri string, format infrav1.ImageExportFormat) (string, error) {
+	args := []string{
+		"-client_id=api",
+		fmt.Sprintf("-timeout=%s", exportToolTimeout),
+		fmt.Sprintf("-source_image=projects/%s/global/images/%s", s.scope.Project(), imageName),
+		fmt.Sprintf("-destination_uri=%s", uri),
+	}
+	if format != infrav1.RawImageExportFormat {
+		args = append(args, fmt.Sprintf("-format=%s", format))
+	}
+
+	builds, err := s.buildsService(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.Log.Info("Exporting disk image", "image", imageName, "uri", uri)
+	op, err := builds.Create(s.scope.Project(), &cloudbuild.Build{
+		Steps: []*cloudbuild.BuildStep{{
+			Name: exportBuilderImage,
+			Args: args,
+			Env:  []string{"BUILD_ID=$BUILD_ID"},
+		}},
+		Timeout: exportTimeout,
+		Tags:    []string{"gce-daisy", "gce-daisy-image-export"},
+	}).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to start export of image to %s: %w", uri, err)
+	}
+
+	metadata := &cloudbuild.BuildOperationMetadata{}
+	if err := json.Unmarshal(op.Metadata, metadata); err != nil || metadata.Build == nil {
+		return "", fmt.Errorf("failed to get the export build of operation %s: %w", op.Name, err)
+	}
+
+	return metadata.Build.Id, nil
+}
+
+// deleteExports deletes the exported objects of the produced image.
+func (s *Service) deleteExports(ctx context.Context) error {
+	for _, status := range s.scope.ImageExportStatuses() {
+		objects, err := s.objectsService(ctx)
+		if err != nil {
+			return err
+		}
+		bucket, object := splitURI(status.URI)
+		s.Log.Info("Deleting exported image", "uri", status.URI)
+		if err := objects.Delete(bucket, object).Context(ctx).Do(); err != nil && !gcperrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete exported image %s: %w", status.URI, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) findExportStatus(uri string) *infrav1.ImageExportStatus {
+	for _, status := range s.scope.ImageExportStatuses() {
+		if status.URI == uri {
+			return &status
+		}
+	}
+
+	return nil
+}
+
+// exportURI returns the URI of the object the image is exported to in the given destination.
+func exportURI(destination, imageName string, format infrav1.ImageExportFormat) string {
+	extension := "." + string(format)
+	if format == infrav1.RawImageExportFormat {
+		extension = ".tar.gz"
+	}
+
+	return fmt.Sprintf("%s/%s%s", strings.TrimSuffix(destination, "/"), imageName, extension)
+}
+
+// splitURI returns the bucket and the object of the given gs:// URI.
+func splitURI(uri string) (string, string) {
+	bucket, object, _ := strings.Cut(strings.TrimPrefix(uri, "gs://"), "/")
+
+	return bucket, object
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+func TestExportURI(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		format      infrav1.ImageExportFormat
+		want        string
+	}{
+		{
+			name:        "raw image as a tarball",
+			destination: "gs://my-bucket/images",
+			format:      infrav1.RawImageExportFormat,
+			want:        "gs://my-bucket/images/my-image.tar.gz",
+		},
+		{
+			name:        "vmdk",
+			destination: "gs://my-bucket/images",
+			format:      infrav1.VMDKImageExportFormat,
+			want:        "gs://my-bucket/images/my-image.vmdk",
+		},
+		{
+			name:        "vhdx",
+			destination: "gs://my-bucket",
+			format:      infrav1.VHDXImageExportFormat,
+			want:        "gs://my-bucket/my-image.vhdx",
+		},
+		{
+			name:        "destination with a trailing slash",
+			destination: "gs://my-bucket/images/",
+			format:      infrav1.RawImageExportFormat,
+			want:        "gs://my-bucket/images/my-image.tar.gz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(exportURI(tt.destination, "my-image", tt.format)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSplitURI(t *testing.T) {
+	tests := []struct {
+		name       string
+		uri        string
+		wantBucket string
+		wantObject string
+	}{
+		{
+			name:       "object at the root of the bucket",
+			uri:        "gs://my-bucket/my-image.tar.gz",
+			wantBucket: "my-bucket",
+			wantObject: "my-image.tar.gz",
+		},
+		{
+			name:       "object in a folder",
+			uri:        "gs://my-bucket/images/2024/my-image.vmdk",
+			wantBucket: "my-bucket",
+			wantObject: "images/2024/my-image.vmdk",
+		},
+		{
+			name:       "bucket only",
+			uri:        "gs://my-bucket",
+			wantBucket: "my-bucket",
+			wantObject: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			bucket, object := splitURI(tt.uri)
+			g.Expect(bucket).To(Equal(tt.wantBucket))
+			g.Expect(object).To(Equal(tt.wantObject))
+		})
+	}
+}
@@ -19,118 +19,337 @@ package images
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/pkg/errors"
 	"google.golang.org/api/compute/v1"
-	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+const (
+	// imageUserRole allows to use an image to create disks and instances.
+	imageUserRole = "roles/compute.imageUser"
+
+	windowsStartupScriptMetaKey = "windows-startup-script-cmd"
+	sysprepCommand              = "GCESysprep"
 )
 
-// Reconcile ensures that a disk image is created from an instance.
+// Reconcile ensures that the artifact, a disk image by default, is created from an instance.
 func (s *Service) Reconcile(ctx context.Context) error {
 	if !s.scope.IsProvisionerReady() || s.scope.IsReady() {
 		s.Log.Info("Not ready for exporting the image")
+		if !s.scope.IsReady() {
+			s.scope.MarkConditionFalse(infrav1.ImageCreatedCondition, infrav1.WaitingForProvisionerReason, clusterv1.ConditionSeverityInfo, "waiting for the provisioners to complete")
+		}
 		return nil
 	}
 
 	s.Log.Info("Reconciling image creation")
+	if err := s.reconcileArtifact(ctx); err != nil {
+		s.scope.MarkConditionFalse(infrav1.ImageCreatedCondition, infrav1.ImageCreationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	if s.scope.ArtifactRef() == nil {
+		s.scope.MarkConditionFalse(infrav1.ImageCreatedCondition, infrav1.ImageCreationInProgressReason, clusterv1.ConditionSeverityInfo, "the artifact is being created")
+	}
 
-	imageName := s.scope.ImageName()
-	instanceName := s.scope.Name()
+	return nil
+}
+
+// reconcileArtifact stops the instance and creates the artifact from it, it is done once ArtifactRef is set.
+func (s *Service) reconcileArtifact(ctx context.Context) error {
 
-	// Stop the instance
-	if err := s.stopInstance(ctx, instanceName); err != nil {
+	imageName, err := s.scope.EnsureArtifactName()
+	if err != nil {
 		return err
 	}
+	instanceName := s.scope.Name()
 
-	// Wait for the instance to be stopped.
-	instance, err := s.instance.Get(s.scope.Project(), s.scope.Zone(), instanceName).Do()
+	instance, err := s.instance.Get(s.scope.Project(), s.scope.Zone(), instanceName).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("failed to get instance status: %v", err)
+		return fmt.Errorf("failed to get instance status: %w", err)
 	}
+
+	// Stop the instance, Windows instances are generalized with GCESysprep which shuts them down.
+	if s.scope.IsWindows() {
+		err = s.sysprepInstance(ctx, instance)
+	} else {
+		err = s.stopInstance(ctx, instance)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Wait for the instance to be stopped.
 	if instance.Status != "TERMINATED" {
 		s.Log.V(1).Info("The instance is not stopped yet", "status", instance.Status)
 		return nil
 	}
-	// Delete the existing disk image if it exists
-	s.Log.V(1).Info("Ensuring no existing image conflicts", "image", imageName)
-	if err := s.ensureImageDoesNotExist(ctx, imageName); err != nil {
-		return err
+
+	switch s.scope.ArtifactType() {
+	case infrav1.MachineImageArtifactType:
+		return s.reconcileMachineImage(ctx, instance)
+	case infrav1.SnapshotArtifactType:
+		return s.reconcileSnapshot(ctx)
 	}
 
-	// Create the disk image from the instance's boot disk
-	s.Log.Info("Creating disk image from instance", "instance", instanceName, "image", imageName)
-	if err := s.createDiskImage(ctx, instanceName, imageName); err != nil {
+	// Create the disk image from the instance's boot disk.
+	image, err := s.createOrGetImage(ctx, imageName)
+	if err != nil {
 		return err
 	}
+	if image == nil {
+		s.Log.V(1).Info("Disk image is being created", "image", imageName)
+		return nil
+	}
 
 	// Wait for the disk image to be ready
-	s.Log.V(1).Info("Waiting for image readiness", "image", imageName)
-	key := &meta.Key{Name: imageName}
-	image, err := s.images.Get(ctx, key)
+	if image.Status != "READY" {
+		s.Log.V(1).Info("Disk image is not ready yet", "image", imageName)
+		return nil
+	}
+
+	s.scope.SetImageStatus(image)
+
+	if err := s.grantImageUsers(ctx, imageName); err != nil {
+		return err
+	}
+
+	copied, err := s.reconcileCopies(ctx, image)
 	if err != nil {
-		return fmt.Errorf("failed to get image status: %v", err)
+		return err
+	}
+	if !copied {
+		s.Log.V(1).Info("Disk image copies are not ready yet", "image", imageName)
+		return nil
 	}
 
-	if image.Status != "READY" {
-		s.Log.V(1).Info("Disk image is not ready yet", "image", imageName)
+	exported, err := s.reconcileExports(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	if !exported {
+		s.Log.V(1).Info("Disk image exports are not ready yet", "image", imageName)
 		return nil
 	}
 
-	artifactRef := fmt.Sprintf("projects/%s/global/images/%s", s.scope.Project(), imageName)
-	s.scope.SetArtifactRef(artifactRef)
+	s.scope.SetArtifactReady(image.SelfLink)
 	s.Log.Info("Disk image reconciliation successful", "image", imageName)
 	return nil
 }
 
-// stopInstance stops the specified instance and waits for it to stop.
-func (s *Service) stopInstance(ctx context.Context, instanceName string) error {
-	s.Log.Info("Stopping instance", "instance", instanceName)
+// stopInstance stops the instance once, the stop operation is polled on later reconciles.
+func (s *Service) stopInstance(ctx context.Context, instance *compute.Instance) error {
+	stopTarget := operations.Target("stop", "instances", instance.Name)
+	done, err := s.operations.Poll(ctx, stopTarget)
+	if err != nil || !done || instance.Status != "RUNNING" {
+		return err
+	}
 
-	stopCall := s.instance.Stop(s.scope.Project(), s.scope.Zone(), instanceName)
-	_, err := stopCall.Do()
+	s.Log.Info("Stopping instance", "instance", instance.Name)
+	op, err := s.instance.Stop(s.scope.Project(), s.scope.Zone(), instance.Name).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("failed to stop instance: %v", err)
+		return fmt.Errorf("failed to stop instance: %w", err)
 	}
-	return nil
+
+	return s.operations.Track(stopTarget, op)
+}
+
+// sysprepInstance runs GCESysprep on the specified Windows instance, as a startup script run by a reset.
+// GCESysprep shuts the instance down once done.
+func (s *Service) sysprepInstance(ctx context.Context, instance *compute.Instance) error {
+	instanceName := instance.Name
+	resetTarget := operations.Target("reset", "instances", instanceName)
+	if s.scope.IsSysprepStarted() {
+		_, err := s.operations.Poll(ctx, resetTarget)
+		return err
+	}
+
+	metadataTarget := operations.Target("setMetadata", "instances", instanceName)
+	done, err := s.operations.Poll(ctx, metadataTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		s.Log.V(1).Info("Instance metadata is not set yet", "instance", instanceName)
+		return nil
+	}
+
+	metadata := instance.Metadata
+	if metadata == nil {
+		metadata = &compute.Metadata{}
+	}
+	if !hasMetadataItem(metadata, windowsStartupScriptMetaKey, sysprepCommand) {
+		s.Log.Info("Setting GCESysprep as startup script", "instance", instanceName)
+		setMetadataItem(metadata, windowsStartupScriptMetaKey, sysprepCommand)
+		op, err := s.instance.SetMetadata(s.scope.Project(), s.scope.Zone(), instanceName, metadata).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to set instance metadata: %w", err)
+		}
+
+		return s.operations.Track(metadataTarget, op)
+	}
+
+	s.Log.Info("Resetting instance to run GCESysprep", "instance", instanceName)
+	op, err := s.instance.Reset(s.scope.Project(), s.scope.Zone(), instanceName).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to reset instance: %w", err)
+	}
+	s.scope.SetSysprepStarted()
+
+	return s.operations.Track(resetTarget, op)
 }
 
-// ensureImageDoesNotExist deletes the disk image if it already exists.
-func (s *Service) ensureImageDoesNotExist(ctx context.Context, imageName string) error {
-	key := &meta.Key{Name: imageName}
+// hasMetadataItem returns true if the metadata has the given item.
+func hasMetadataItem(metadata *compute.Metadata, key, value string) bool {
+	for _, item := range metadata.Items {
+		if item.Key == key && ptr.Deref(item.Value, "") == value {
+			return true
+		}
+	}
 
-	err := s.images.Delete(ctx, key)
+	return false
+}
+
+// setMetadataItem sets the given item, replacing the item with the same key if any.
+func setMetadataItem(metadata *compute.Metadata, key, value string) {
+	for _, item := range metadata.Items {
+		if item.Key == key {
+			item.Value = ptr.To(value)
+			return
+		}
+	}
+
+	metadata.Items = append(metadata.Items, &compute.MetadataItems{Key: key, Value: ptr.To(value)})
+}
+
+// createOrGetImage creates the disk image if it does not exist yet, otherwise returns the existing one
+// once it is known to be owned by the build. It returns nil while the image is being created.
+func (s *Service) createOrGetImage(ctx context.Context, imageName string) (*compute.Image, error) {
+	insertTarget := operations.Target("insert", "images", imageName)
+	done, err := s.operations.Poll(ctx, insertTarget)
+	if err != nil || !done {
+		return nil, err
+	}
+
+	s.Log.V(1).Info("Looking for image", "image", imageName)
+	image, err := s.images.Get(ctx, meta.GlobalKey(imageName))
 	if err != nil {
-		if gcperrors.IsNotFound(err) {
-			return nil
+		if !gcperrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get image status: %w", err)
+		}
+
+		s.Log.Info("Creating disk image from instance", "instance", s.scope.Name(), "image", imageName)
+		op, err := s.imageOperations.Insert(s.scope.Project(), s.scope.ImageSpec()).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create disk image: %w", err)
 		}
-		return fmt.Errorf("failed to delete existing image: %v", err)
+		s.Log.Info("Disk image creation initiated", "image", imageName)
+
+		return nil, s.operations.Track(insertTarget, op)
 	}
 
-	s.Log.V(1).Info("Waiting for existing image deletion", "image", imageName)
-	time.Sleep(5 * time.Second)
-	return nil
+	// The name template may render the name of an image of another build, e.g. without a timestamp.
+	if !infrav1.Labels(image.Labels).HasOwned(s.scope.Name()) {
+		return nil, gcperrors.NewNameConflictError(fmt.Sprintf("image %s", imageName))
+	}
+
+	return image, nil
 }
 
-// createDiskImage creates a new disk image from the instance's boot disk.
-func (s *Service) createDiskImage(ctx context.Context, instanceName, imageName string) error {
-	image := &compute.Image{
-		Name: imageName,
-		SourceDisk: fmt.Sprintf("projects/%s/zones/%s/disks/%s",
-			s.scope.Project(), s.scope.Zone(), instanceName),
-		Description: fmt.Sprintf("Custom disk image created from instance: %s", instanceName),
+// grantImageUsers grants roles/compute.imageUser on the given image to the image users.
+func (s *Service) grantImageUsers(ctx context.Context, imageName string) error {
+	users := s.scope.ImageUsers()
+	if len(users) == 0 {
+		return nil
 	}
 
-	key := &meta.Key{Name: imageName}
-	err := s.images.Insert(ctx, key, image)
+	key := meta.GlobalKey(imageName)
+	policy, err := s.images.GetIamPolicy(ctx, key)
 	if err != nil {
-		return fmt.Errorf("failed to create disk image: %v", err)
+		return fmt.Errorf("failed to get IAM policy of image %s: %w", imageName, err)
 	}
 
-	s.Log.Info("Disk image creation initiated", "image", imageName)
+	var binding *compute.Binding
+	for _, b := range policy.Bindings {
+		if b.Role == imageUserRole && b.Condition == nil {
+			binding = b
+			break
+		}
+	}
+	if binding == nil {
+		binding = &compute.Binding{Role: imageUserRole}
+		policy.Bindings = append(policy.Bindings, binding)
+	}
+
+	members := sets.New(binding.Members...)
+	if !members.HasAll(users...) {
+		binding.Members = sets.List(members.Insert(users...))
+
+		s.Log.Info("Granting image users access to image", "image", imageName, "users", users)
+		// The policy etag guards against concurrent modifications.
+		policy, err = s.images.SetIamPolicy(ctx, key, &compute.GlobalSetPolicyRequest{Policy: policy})
+		if err != nil {
+			return fmt.Errorf("failed to set IAM policy of image %s: %w", imageName, err)
+		}
+	}
+
+	s.scope.SetImageIAMBindings(policy.Bindings)
 	return nil
 }
 
+// Delete applies the retention policy of the image family after the build, and deletes the produced artifact, and the
+// copies and exports of an image, once the GCPBuild is deleted, if the artifact deletion policy says so.
 func (s *Service) Delete(ctx context.Context) error {
-	return nil
+	if !s.scope.IsDeleting() {
+		// The retention policy is applied by the cleanup that follows a successful build.
+		return s.applyRetentionPolicy(ctx)
+	}
+	if s.scope.ArtifactRef() == nil || s.scope.ArtifactDeletionPolicy() != infrav1.ArtifactDeletionPolicyDelete {
+		return nil
+	}
+
+	artifact := s.scope.ArtifactReference()
+	switch artifact.Type {
+	case infrav1.MachineImageArtifactType:
+		return s.deleteMachineImage(ctx, artifact.Name)
+	case infrav1.SnapshotArtifactType:
+		return s.deleteSnapshot(ctx, artifact.Name)
+	}
+
+	imageName := artifact.Name
+	deleteTarget := operations.Target("delete", "images", imageName)
+	done, err := s.operations.Poll(ctx, deleteTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(operations.ErrInProgress, "image %s is being deleted", imageName)
+	}
+
+	s.Log.Info("Deleting image", "image", imageName)
+	op, err := s.imageOperations.Delete(s.scope.Project(), imageName).Context(ctx).Do()
+	if err == nil {
+		if err := s.operations.Track(deleteTarget, op); err != nil {
+			return err
+		}
+
+		return errors.Wrapf(operations.ErrInProgress, "image %s is being deleted", imageName)
+	}
+	if !gcperrors.IsNotFound(err) {
+		s.Log.Error(err, "Error deleting image", "image", imageName)
+		return err
+	}
+
+	if err := s.deleteCopies(ctx); err != nil {
+		return err
+	}
+
+	return s.deleteExports(ctx)
 }
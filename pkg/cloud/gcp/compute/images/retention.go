@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// applyRetentionPolicy deprecates or deletes the older images of the family of the produced image once it is ready.
+// Only images created by this provider are considered. The policy is applied once and on a best effort basis:
+// failures are reported as events and in the ImageRetentionApplied condition, they don't fail the build.
+func (s *Service) applyRetentionPolicy(ctx context.Context) error {
+	policy := s.scope.ImageRetentionPolicy()
+	family := s.scope.ImageFamily()
+	artifact := s.scope.ArtifactReference()
+	if policy == nil || family == nil || !s.scope.IsReady() || artifact.Type != infrav1.ImageArtifactType || artifact.SelfLink == nil {
+		return nil
+	}
+
+	if s.scope.IsImageRetentionStarted() {
+		return s.pollRetentionOperations(ctx)
+	}
+
+	s.Log.V(1).Info("Applying retention policy to image family", "family", *family)
+	familyImages, err := s.images.List(ctx, filter.Regexp("family", *family))
+	if err != nil {
+		s.retentionFailed(fmt.Errorf("failed to list images of family %s: %w", *family, err))
+		return nil
+	}
+
+	state := strings.ToUpper(string(ptr.Deref(policy.DeprecationState, infrav1.ImageDeprecationStateDeprecated)))
+	now := time.Now()
+	for i, olderImage := range olderFamilyImages(familyImages, artifact.Name) {
+		if exceedsRetention(policy, i, olderImage, now) {
+			s.Log.Info("Deleting image exceeding the retention policy", "image", olderImage.Name, "family", *family)
+			op, err := s.imageOperations.Delete(s.scope.Project(), olderImage.Name).Context(ctx).Do()
+			if err == nil {
+				err = s.operations.Track(operations.Target("delete", "images", olderImage.Name), op)
+			}
+			if err != nil && !gcperrors.IsNotFound(err) {
+				s.retentionFailed(fmt.Errorf("failed to delete image %s: %w", olderImage.Name, err))
+			}
+			continue
+		}
+
+		if !needsDeprecation(olderImage, state, *artifact.SelfLink) {
+			continue
+		}
+
+		s.Log.Info("Deprecating image", "image", olderImage.Name, "state", state, "replacement", artifact.Name)
+		op, err := s.imageOperations.Deprecate(s.scope.Project(), olderImage.Name, &compute.DeprecationStatus{
+			State:       state,
+			Replacement: *artifact.SelfLink,
+		}).Context(ctx).Do()
+		if err == nil {
+			err = s.operations.Track(operations.Target("deprecate", "images", olderImage.Name), op)
+		}
+		if err != nil {
+			s.retentionFailed(fmt.Errorf("failed to deprecate image %s: %w", olderImage.Name, err))
+		}
+	}
+
+	if !s.scope.IsImageRetentionFailed() {
+		s.scope.MarkConditionFalse(infrav1.ImageRetentionAppliedCondition, infrav1.ImageRetentionInProgressReason, clusterv1.ConditionSeverityInfo, "the older images of the family are being deprecated or deleted")
+	}
+
+	return s.pollRetentionOperations(ctx)
+}
+
+// pollRetentionOperations waits for the deletions and deprecations of the older images of the family.
+func (s *Service) pollRetentionOperations(ctx context.Context) error {
+	targets := append(s.operations.Targets("delete", "images"), s.operations.Targets("deprecate", "images")...)
+	var pending []string
+	for _, target := range targets {
+		done, err := s.operations.Poll(ctx, target)
+		if err != nil && !gcperrors.IsNotFound(err) {
+			s.retentionFailed(err)
+		}
+		if !done {
+			pending = append(pending, target)
+		}
+	}
+	if len(pending) > 0 {
+		return errors.Wrapf(operations.ErrInProgress, "retention policy operations %v are running", pending)
+	}
+
+	if !s.scope.IsImageRetentionFailed() {
+		s.scope.MarkConditionTrue(infrav1.ImageRetentionAppliedCondition)
+	}
+
+	return nil
+}
+
+// retentionFailed reports a failure to apply the retention policy, which doesn't fail the build.
+func (s *Service) retentionFailed(err error) {
+	s.Log.Error(err, "Unable to apply the retention policy")
+	s.scope.RecordEvent(corev1.EventTypeWarning, "ImageRetentionFailed", err.Error())
+	s.scope.MarkConditionFalse(infrav1.ImageRetentionAppliedCondition, infrav1.ImageRetentionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+}
+
+// olderFamilyImages returns the images of the family created by this provider other than the given image,
+// newest first.
+func olderFamilyImages(familyImages []*compute.Image, imageName string) []*compute.Image {
+	olderImages := make([]*compute.Image, 0, len(familyImages))
+	for _, familyImage := range familyImages {
+		if familyImage.Name == imageName || !infrav1.Labels(familyImage.Labels).HasProviderOwned() {
+			continue
+		}
+		olderImages = append(olderImages, familyImage)
+	}
+	// The creation timestamps are RFC 3339 timestamps in the Pacific time, their offset changes with the daylight
+	// saving time so they are compared as times.
+	sort.SliceStable(olderImages, func(i, j int) bool {
+		createdI, errI := time.Parse(time.RFC3339, olderImages[i].CreationTimestamp)
+		createdJ, errJ := time.Parse(time.RFC3339, olderImages[j].CreationTimestamp)
+		if errI != nil || errJ != nil {
+			return olderImages[i].CreationTimestamp > olderImages[j].CreationTimestamp
+		}
+
+		return createdI.After(createdJ)
+	})
+
+	return olderImages
+}
+
+// exceedsRetention returns true if the image at the given position among the older images of the family,
+// newest first, has to be deleted. Images with an invalid creation timestamp are kept.
+func exceedsRetention(policy *infrav1.ImageRetentionPolicy, position int, image *compute.Image, now time.Time) bool {
+	// The new image counts towards KeepLast.
+	if policy.KeepLast != nil && int32(position+1) >= *policy.KeepLast {
+		return true
+	}
+
+	if policy.MaxAge != nil {
+		created, err := time.Parse(time.RFC3339, image.CreationTimestamp)
+		if err != nil {
+			return false
+		}
+
+		return now.Sub(created) > policy.MaxAge.Duration
+	}
+
+	return false
+}
+
+// deprecationRanks ranks the deprecation states from the least to the most restrictive.
+var deprecationRanks = map[string]int{
+	"":           0,
+	"ACTIVE":     0,
+	"DEPRECATED": 1,
+	"OBSOLETE":   2,
+	"DELETED":    3,
+}
+
+// needsDeprecation returns true if the image has to be moved to the given deprecation state with the given
+// replacement. The deprecation state of an image is never lowered.
+func needsDeprecation(image *compute.Image, state, replacement string) bool {
+	if image.Deprecated == nil {
+		return true
+	}
+
+	current := deprecationRanks[image.Deprecated.State]
+	if current != deprecationRanks[state] {
+		return current < deprecationRanks[state]
+	}
+
+	return image.Deprecated.Replacement != replacement
+}
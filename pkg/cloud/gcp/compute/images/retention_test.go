@@ -0,0 +1,254 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+func familyImage(name, created string, owned bool) *compute.Image {
+	image := &compute.Image{Name: name, CreationTimestamp: created}
+	if owned {
+		image.Labels = map[string]string{infrav1.NameGCPProviderOwned + "build": string(infrav1.ResourceLifecycleOwned)}
+	}
+
+	return image
+}
+
+func imageNames(images []*compute.Image) []string {
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		names = append(names, image.Name)
+	}
+
+	return names
+}
+
+func TestOlderFamilyImages(t *testing.T) {
+	tests := []struct {
+		name   string
+		images []*compute.Image
+		want   []string
+	}{
+		{
+			name:   "no images",
+			images: nil,
+			want:   []string{},
+		},
+		{
+			name: "only the new image",
+			images: []*compute.Image{
+				familyImage("new", "2024-03-01T00:00:00Z", true),
+			},
+			want: []string{},
+		},
+		{
+			name: "newest first",
+			images: []*compute.Image{
+				familyImage("jan", "2024-01-01T00:00:00Z", true),
+				familyImage("new", "2024-03-01T00:00:00Z", true),
+				familyImage("feb", "2024-02-01T00:00:00Z", true),
+				familyImage("dec", "2023-12-01T00:00:00Z", true),
+			},
+			want: []string{"feb", "jan", "dec"},
+		},
+		{
+			name: "timestamps with offsets",
+			images: []*compute.Image{
+				familyImage("morning", "2024-02-01T08:00:00.000-08:00", true),
+				familyImage("evening", "2024-02-01T20:00:00.000-08:00", true),
+			},
+			want: []string{"evening", "morning"},
+		},
+		{
+			name: "timestamps across a daylight saving time change",
+			images: []*compute.Image{
+				familyImage("before", "2024-11-03T01:30:00.000-07:00", true),
+				familyImage("after", "2024-11-03T01:10:00.000-08:00", true),
+			},
+			want: []string{"after", "before"},
+		},
+		{
+			name: "images not created by the provider are skipped",
+			images: []*compute.Image{
+				familyImage("jan", "2024-01-01T00:00:00Z", true),
+				familyImage("external", "2024-02-01T00:00:00Z", false),
+			},
+			want: []string{"jan"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(imageNames(olderFamilyImages(tt.images, "new"))).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestExceedsRetention(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		policy   *infrav1.ImageRetentionPolicy
+		position int
+		created  string
+		want     bool
+	}{
+		{
+			name:     "no limits",
+			policy:   &infrav1.ImageRetentionPolicy{},
+			position: 10,
+			created:  "2020-01-01T00:00:00Z",
+			want:     false,
+		},
+		{
+			name:     "within keepLast",
+			policy:   &infrav1.ImageRetentionPolicy{KeepLast: ptr.To[int32](3)},
+			position: 1,
+			created:  "2024-02-01T00:00:00Z",
+			want:     false,
+		},
+		{
+			name:     "new image counts towards keepLast",
+			policy:   &infrav1.ImageRetentionPolicy{KeepLast: ptr.To[int32](3)},
+			position: 2,
+			created:  "2024-02-01T00:00:00Z",
+			want:     true,
+		},
+		{
+			name:     "keepLast of one deletes all older images",
+			policy:   &infrav1.ImageRetentionPolicy{KeepLast: ptr.To[int32](1)},
+			position: 0,
+			created:  "2024-02-29T00:00:00Z",
+			want:     true,
+		},
+		{
+			name:     "younger than maxAge",
+			policy:   &infrav1.ImageRetentionPolicy{MaxAge: &metav1.Duration{Duration: 30 * 24 * time.Hour}},
+			position: 5,
+			created:  "2024-02-15T00:00:00Z",
+			want:     false,
+		},
+		{
+			name:     "older than maxAge",
+			policy:   &infrav1.ImageRetentionPolicy{MaxAge: &metav1.Duration{Duration: 30 * 24 * time.Hour}},
+			position: 0,
+			created:  "2024-01-15T00:00:00Z",
+			want:     true,
+		},
+		{
+			name: "older than maxAge within keepLast",
+			policy: &infrav1.ImageRetentionPolicy{
+				KeepLast: ptr.To[int32](3),
+				MaxAge:   &metav1.Duration{Duration: 30 * 24 * time.Hour},
+			},
+			position: 0,
+			created:  "2024-01-15T00:00:00Z",
+			want:     true,
+		},
+		{
+			name:     "invalid timestamp is kept",
+			policy:   &infrav1.ImageRetentionPolicy{MaxAge: &metav1.Duration{Duration: time.Hour}},
+			position: 0,
+			created:  "yesterday",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			image := &compute.Image{Name: "older", CreationTimestamp: tt.created}
+			g.Expect(exceedsRetention(tt.policy, tt.position, image, now)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNeedsDeprecation(t *testing.T) {
+	const replacement = "projects/p/global/images/new"
+	tests := []struct {
+		name       string
+		deprecated *compute.DeprecationStatus
+		state      string
+		want       bool
+	}{
+		{
+			name:  "active image",
+			state: "DEPRECATED",
+			want:  true,
+		},
+		{
+			name:       "explicitly active image",
+			deprecated: &compute.DeprecationStatus{State: "ACTIVE"},
+			state:      "DEPRECATED",
+			want:       true,
+		},
+		{
+			name:       "already deprecated with the same replacement",
+			deprecated: &compute.DeprecationStatus{State: "DEPRECATED", Replacement: replacement},
+			state:      "DEPRECATED",
+			want:       false,
+		},
+		{
+			name:       "deprecated with another replacement",
+			deprecated: &compute.DeprecationStatus{State: "DEPRECATED", Replacement: "projects/p/global/images/old"},
+			state:      "DEPRECATED",
+			want:       true,
+		},
+		{
+			name:       "deprecated moved to obsolete",
+			deprecated: &compute.DeprecationStatus{State: "DEPRECATED", Replacement: replacement},
+			state:      "OBSOLETE",
+			want:       true,
+		},
+		{
+			name:       "obsolete is not moved back to deprecated",
+			deprecated: &compute.DeprecationStatus{State: "OBSOLETE", Replacement: "projects/p/global/images/old"},
+			state:      "DEPRECATED",
+			want:       false,
+		},
+		{
+			name:       "deleted is not moved back to deprecated",
+			deprecated: &compute.DeprecationStatus{State: "DELETED"},
+			state:      "DEPRECATED",
+			want:       false,
+		},
+		{
+			name:       "deleted is not moved back to obsolete",
+			deprecated: &compute.DeprecationStatus{State: "DELETED"},
+			state:      "OBSOLETE",
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			image := &compute.Image{Name: "older", Deprecated: tt.deprecated}
+			g.Expect(needsDeprecation(image, tt.state, replacement)).To(Equal(tt.want))
+		})
+	}
+}
@@ -23,8 +23,14 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
 	"github.com/go-logr/logr"
+	"google.golang.org/api/cloudbuild/v1"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/storage/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
 )
 
 const ServiceName = "image-reconciler"
@@ -36,43 +42,146 @@ type instanceInterface interface {
 	List(project string, zone string) *compute.InstancesListCall
 	Start(project string, zone string, instance string) *compute.InstancesStartCall
 	Stop(project string, zone string, instance string) *compute.InstancesStopCall
+	Reset(project string, zone string, instance string) *compute.InstancesResetCall
+	SetMetadata(project string, zone string, instance string, metadata *compute.Metadata) *compute.InstancesSetMetadataCall
 }
 
 type imageInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Image, error)
 	List(ctx context.Context, fl *filter.F, options ...k8scloud.Option) ([]*compute.Image, error)
-	Insert(ctx context.Context, key *meta.Key, obj *compute.Image, options ...k8scloud.Option) error
 	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+	GetIamPolicy(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Policy, error)
+	SetIamPolicy(ctx context.Context, key *meta.Key, req *compute.GlobalSetPolicyRequest, options ...k8scloud.Option) (*compute.Policy, error)
+}
+
+type imageOperationsInterface interface {
+	Insert(project string, image *compute.Image) *compute.ImagesInsertCall
+	Delete(project string, image string) *compute.ImagesDeleteCall
+	Deprecate(project string, image string, deprecationstatus *compute.DeprecationStatus) *compute.ImagesDeprecateCall
+}
+
+type imageCopyInterface interface {
+	Get(project string, image string) *compute.ImagesGetCall
+	Insert(project string, image *compute.Image) *compute.ImagesInsertCall
+	Delete(project string, image string) *compute.ImagesDeleteCall
+}
+
+type machineImageInterface interface {
+	Get(project string, machineImage string) *compute.MachineImagesGetCall
+	Insert(project string, machineImage *compute.MachineImage) *compute.MachineImagesInsertCall
+	Delete(project string, machineImage string) *compute.MachineImagesDeleteCall
+}
+
+type snapshotInterface interface {
+	Get(project string, snapshot string) *compute.SnapshotsGetCall
+	Insert(project string, snapshot *compute.Snapshot) *compute.SnapshotsInsertCall
+	Delete(project string, snapshot string) *compute.SnapshotsDeleteCall
+}
+
+type buildInterface interface {
+	Create(projectID string, build *cloudbuild.Build) *cloudbuild.ProjectsBuildsCreateCall
+	Get(projectID string, id string) *cloudbuild.ProjectsBuildsGetCall
+}
+
+type objectInterface interface {
+	Get(bucket string, object string) *storage.ObjectsGetCall
+	Delete(bucket string, object string) *storage.ObjectsDeleteCall
 }
 
 // Scope is an interface that holds methods used for reconciling images.
 type Scope interface {
 	cloud.Build
+	operations.Scope
 	InstanceImageSpec() *compute.AttachedDisk
 	IsProvisionerReady() bool
-	ImageName() string
+	EnsureArtifactName() (string, error)
+	ArtifactType() infrav1.ArtifactType
+	ArtifactReference() *infrav1.ArtifactReference
+	ImageSpec() *compute.Image
+	MachineImageSpec(instance *compute.Instance) *compute.MachineImage
+	SnapshotSpec() *compute.Snapshot
 	Name() string
 	IsReady() bool
 	GetComputeService() *compute.Service
-	SetArtifactRef(artificatRef string)
+	CloudBuildService(ctx context.Context) (*cloudbuild.Service, error)
+	StorageService(ctx context.Context) (*storage.Service, error)
+	SetArtifactReady(selfLink string)
+	SetImageStatus(image *compute.Image)
+	ImageFamily() *string
+	ImageRetentionPolicy() *infrav1.ImageRetentionPolicy
+	IsImageRetentionStarted() bool
+	IsImageRetentionFailed() bool
+	RecordEvent(eventType, reason, message string)
+	IsDeleting() bool
+	ArtifactRef() *string
+	ArtifactDeletionPolicy() infrav1.ArtifactDeletionPolicy
+	ImageExports() []infrav1.ImageExport
+	ImageExportStatuses() []infrav1.ImageExportStatus
+	SetImageExportStatus(status infrav1.ImageExportStatus)
+	ImageCopies() []infrav1.ImageCopy
+	ImageCopyStatuses() []infrav1.ImageCopyStatus
+	SetImageCopyStatus(status infrav1.ImageCopyStatus)
+	ImageCopySpec(target infrav1.ImageCopy, source *compute.Image) *compute.Image
+	TargetComputeService(ctx context.Context, credentialsRef *corev1.SecretReference) (*compute.Service, error)
+	ImageUsers() []string
+	IsWindows() bool
+	IsSysprepStarted() bool
+	SetSysprepStarted()
+	SetImageIAMBindings(bindings []*compute.Binding)
 }
 
 // Service implements the reconcile logic for managing images in GCP.
 type Service struct {
-	scope    Scope
-	instance instanceInterface
-	images   imageInterface
-	Log      logr.Logger
+	scope           Scope
+	instance        instanceInterface
+	images          imageInterface
+	imageOperations imageOperationsInterface
+	machineImages   machineImageInterface
+	snapshots       snapshotInterface
+	builds          buildInterface
+	objects         objectInterface
+	operations      *operations.Tracker
+	Log             logr.Logger
 }
 
 // New returns a new instance of Service for image creation.
 func New(scope Scope) *Service {
 	return &Service{
-		scope:    scope,
-		instance: compute.NewInstancesService(scope.GetComputeService()),
-		images:   scope.Cloud().Images(),
-		Log:      scope.Log(ServiceName),
+		scope:           scope,
+		instance:        compute.NewInstancesService(scope.GetComputeService()),
+		images:          scope.Cloud().Images(),
+		imageOperations: compute.NewImagesService(scope.GetComputeService()),
+		machineImages:   compute.NewMachineImagesService(scope.GetComputeService()),
+		snapshots:       compute.NewSnapshotsService(scope.GetComputeService()),
+		operations:      operations.New(scope),
+		Log:             scope.Log(ServiceName),
 	}
 }
 
 var _ cloud.Reconciler = &Service{}
+
+// buildsService returns the Cloud Build builds service, created on first use as only image exports need it.
+func (s *Service) buildsService(ctx context.Context) (buildInterface, error) {
+	if s.builds == nil {
+		cloudBuildSvc, err := s.scope.CloudBuildService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.builds = cloudbuild.NewProjectsBuildsService(cloudBuildSvc)
+	}
+
+	return s.builds, nil
+}
+
+// objectsService returns the Cloud Storage objects service, created on first use as only image exports need it.
+func (s *Service) objectsService(ctx context.Context) (objectInterface, error) {
+	if s.objects == nil {
+		storageSvc, err := s.scope.StorageService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.objects = storage.NewObjectsService(storageSvc)
+	}
+
+	return s.objects, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// checkReadinessTimeout fails the build if the builder instance did not become ready in time.
+func (s *Service) checkReadinessTimeout(ctx context.Context, instance *compute.Instance) error {
+	if s.scope.IsInstanceReady() {
+		return nil
+	}
+
+	created, err := time.Parse(time.RFC3339, instance.CreationTimestamp)
+	if err != nil || time.Since(created) < s.scope.ReadinessTimeout() {
+		return nil
+	}
+
+	s.captureSerialOutput(ctx, instance, true)
+	message := fmt.Sprintf("instance %s did not become ready within %s", instance.Name, s.scope.ReadinessTimeout())
+	s.scope.MarkInstanceNotReady(infrav1.InstanceReadinessTimeoutReason, message)
+	s.scope.SetFailure(infrav1.InstanceReadinessTimeoutReason, message)
+
+	return errors.New(message)
+}
+
+// reconcileReadiness checks whether the builder instance is RUNNING and exposes the guest attribute
+// of the readiness gate, and reports the outcome in the InstanceReady condition.
+func (s *Service) reconcileReadiness(ctx context.Context, instance *compute.Instance) error {
+	if s.scope.IsInstanceReady() {
+		return nil
+	}
+
+	if instance.Status != "RUNNING" {
+		s.scope.MarkInstanceNotReady(infrav1.WaitingForInstanceRunningReason, fmt.Sprintf("instance %s is %s", instance.Name, instance.Status))
+		return nil
+	}
+
+	queryPath := s.scope.ReadinessGuestAttribute()
+	if queryPath == "" {
+		s.scope.MarkInstanceReady()
+		return nil
+	}
+
+	attributes, err := s.guestAttributes.GetGuestAttributes(s.scope.Project(), s.scope.Zone(), instance.Name).QueryPath(queryPath).Context(ctx).Do()
+	if err != nil && !gcperrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get guest attribute %s", queryPath)
+	}
+
+	if err == nil && hasGuestAttribute(attributes, queryPath) {
+		s.Log.V(1).Info("Instance is ready", "instance", instance.Name, "guestAttribute", queryPath)
+		s.scope.MarkInstanceReady()
+		return nil
+	}
+
+	s.scope.MarkInstanceNotReady(infrav1.WaitingForGuestAttributeReason, fmt.Sprintf("waiting for guest attribute %s on instance %s", queryPath, instance.Name))
+	return nil
+}
+
+// hasGuestAttribute returns true if any key of the namespace is set when the query path ends with "/".
+// A missing key is reported as not found by the API.
+func hasGuestAttribute(attributes *compute.GuestAttributes, queryPath string) bool {
+	if strings.HasSuffix(queryPath, "/") {
+		return attributes.QueryValue != nil && len(attributes.QueryValue.Items) > 0
+	}
+
+	return true
+}
@@ -17,45 +17,86 @@ package instances
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/pkg/errors"
 	"google.golang.org/api/compute/v1"
 	"k8s.io/utils/ptr"
-	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
 )
 
+// ErrInstancePreempted is returned when the builder instance was preempted or terminated externally
+// and has been removed so that it gets recreated on the next reconcile.
+var ErrInstancePreempted = errors.New("builder instance was preempted")
+
 // Reconcile reconcile machine instance.
 func (s *Service) Reconcile(ctx context.Context) error {
 	s.Log.Info("Reconciling instance resources")
+	if err := s.resolveSourceImage(ctx); err != nil {
+		return err
+	}
+
 	instance, err := s.createOrGetInstance(ctx)
+	if err == nil && instance == nil {
+		s.scope.MarkConditionFalse(infrav1.InstanceProvisionedCondition, infrav1.InstanceCreatingReason, clusterv1.ConditionSeverityInfo, "the instance is being created")
+		return nil
+	}
 	if err != nil {
+		switch {
+		case errors.Is(err, ErrInstancePreempted):
+			s.scope.MarkConditionFalse(infrav1.InstanceProvisionedCondition, infrav1.InstancePreemptedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		case errors.Is(err, ErrZoneResourcesExhausted):
+			s.scope.MarkConditionFalse(infrav1.InstanceProvisionedCondition, infrav1.ZoneResourcesExhaustedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		default:
+			s.scope.MarkConditionFalse(infrav1.InstanceProvisionedCondition, infrav1.InstanceProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		}
 		return err
 	}
+	s.scope.MarkConditionTrue(infrav1.InstanceProvisionedCondition)
 
-	//machineName := s.scope.Name()
-	//zone := s.scope.Zone()
-	//project := s.scope.Project()
+	s.captureSerialOutput(ctx, instance, false)
+	if err := s.checkReadinessTimeout(ctx, instance); err != nil {
+		return err
+	}
 
-	var publicIP string
-	for _, iface := range instance.NetworkInterfaces {
-		for _, ac := range iface.AccessConfigs {
-			publicIP = ac.NatIP
-			break
+	if s.scope.IsWindows() {
+		// The password can only be read from a running instance, e.g. not once GCESysprep has shut it down.
+		if instance.Status != "RUNNING" {
+			s.scope.SetInstanceStatus(infrav1.InstanceStatus(instance.Status))
+			return nil
 		}
+
+		ready, err := s.reconcileWindowsPassword(ctx, instance)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			s.Log.Info("Waiting for the guest agent to set the windows password", "instance", instance.Name)
+			s.scope.SetInstanceStatus(infrav1.InstanceStatus(instance.Status))
+			return nil
+		}
+	}
+
+	host := s.scope.InstanceHost(instance)
+	if host == "" {
+		s.Log.Info("Waiting for the instance address to be assigned", "instance", instance.Name)
+		s.scope.SetInstanceStatus(infrav1.InstanceStatus(instance.Status))
+		return nil
 	}
 
-	//TODO things, after the machine is created
-	err = s.scope.EnsureCredentialsSecret(ctx, publicIP)
+	err = s.scope.EnsureCredentialsSecret(ctx, host)
 	if err != nil {
 		return err
 	}
 	s.scope.SetInstanceID(instance.Name)
 	s.scope.SetInstanceStatus(infrav1.InstanceStatus(instance.Status))
 
-	return nil
+	return s.reconcileReadiness(ctx, instance)
 }
 
 // Delete delete machine instance.
@@ -64,8 +105,17 @@ func (s *Service) Delete(ctx context.Context) error {
 	instanceSpec := s.scope.InstanceSpec(s.Log)
 	instanceName := instanceSpec.Name
 	instanceKey := meta.ZonalKey(instanceName, s.scope.Zone())
+	deleteTarget := operations.Target("delete", "instances", instanceName)
+	done, err := s.operations.Poll(ctx, deleteTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(operations.ErrInProgress, "instance %s is being deleted", instanceName)
+	}
+
 	s.Log.V(1).Info("Looking for instance before deleting", "name", instanceName, "zone", s.scope.Zone())
-	_, err := s.instances.Get(ctx, instanceKey)
+	_, err = s.instances.Get(ctx, instanceKey)
 	if err != nil {
 		if !gcperrors.IsNotFound(err) {
 			s.Log.Error(err, "Error looking for instance before deleting", "name", instanceName)
@@ -76,7 +126,15 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	s.Log.V(1).Info("Deleting instance", "name", instanceName, "zone", s.scope.Zone())
-	return gcperrors.IgnoreNotFound(s.instances.Delete(ctx, instanceKey))
+	op, err := s.instanceOperations.Delete(s.scope.Project(), s.scope.Zone(), instanceName).Context(ctx).Do()
+	if err != nil {
+		return gcperrors.IgnoreNotFound(err)
+	}
+	if err := s.operations.Track(deleteTarget, op); err != nil {
+		return err
+	}
+
+	return errors.Wrapf(operations.ErrInProgress, "instance %s is being deleted", instanceName)
 }
 
 func (s *Service) createOrGetInstance(ctx context.Context) (*compute.Instance, error) {
@@ -97,6 +155,17 @@ func (s *Service) createOrGetInstance(ctx context.Context) (*compute.Instance, e
 		})
 	}
 
+	// Wait for the creation of the instance, it is polled on later reconciles.
+	insertTarget := operations.Target("insert", "instances", instanceName)
+	done, err := s.operations.Poll(ctx, insertTarget)
+	if err != nil {
+		return nil, s.fallbackZone(ctx, insertError(instanceName, err))
+	}
+	if !done {
+		s.Log.V(1).Info("Instance is being created", "name", instanceName, "zone", s.scope.Zone())
+		return nil, nil
+	}
+
 	s.Log.V(1).Info("Looking for instance", "name", instanceName, "zone", s.scope.Zone())
 	instance, err := s.instances.Get(ctx, instanceKey)
 	if err != nil {
@@ -105,17 +174,95 @@ func (s *Service) createOrGetInstance(ctx context.Context) (*compute.Instance, e
 			return nil, err
 		}
 
-		s.Log.V(1).Info("Creating an instance", "name", instanceName, "zone", s.scope.Zone())
-		if err := s.instances.Insert(ctx, instanceKey, instanceSpec); err != nil {
-			s.Log.Error(err, "Error creating an instance", "name", instanceName, "zone", s.scope.Zone())
-			return nil, err
+		if s.scope.GetInstanceID() != nil && !s.scope.IsProvisionerReady() {
+			return nil, s.recoverLostInstance(ctx, instanceKey, nil)
 		}
 
-		instance, err = s.instances.Get(ctx, instanceKey)
+		s.Log.V(1).Info("Creating an instance", "name", instanceName, "zone", s.scope.Zone())
+		op, err := s.instanceOperations.Insert(s.scope.Project(), s.scope.Zone(), instanceSpec).Context(ctx).Do()
 		if err != nil {
-			return nil, err
+			s.Log.Error(err, "Error creating an instance", "name", instanceName, "zone", s.scope.Zone())
+			return nil, s.fallbackZone(ctx, insertError(instanceName, err))
+		}
+		if err := s.operations.Track(insertTarget, op); err != nil {
+			return nil, s.fallbackZone(ctx, insertError(instanceName, err))
 		}
+
+		return nil, nil
+	}
+
+	if isTerminated(instance) && !s.scope.IsProvisionerReady() {
+		return nil, s.recoverLostInstance(ctx, instanceKey, instance)
 	}
+	s.scope.ClearExhaustedZones()
 
 	return instance, nil
 }
+
+// recoverLostInstance removes a preempted or externally terminated builder instance so that it gets recreated,
+// unless the preemption retry budget is exhausted in which case the build is marked as failed.
+func (s *Service) recoverLostInstance(ctx context.Context, instanceKey *meta.Key, instance *compute.Instance) error {
+	status := "DELETED"
+	if instance != nil {
+		status = instance.Status
+	}
+
+	// Wait for the deletion of the terminated instance started on a previous reconcile.
+	deleteTarget := operations.Target("delete", "instances", instanceKey.Name)
+	done, err := s.operations.Poll(ctx, deleteTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(ErrInstancePreempted, "instance %s terminated with status %s, waiting for its deletion", instanceKey.Name, status)
+	}
+
+	if s.scope.Preemptions() >= s.scope.MaxPreemptionRetries() {
+		if instance != nil {
+			s.captureSerialOutput(ctx, instance, true)
+		}
+		message := fmt.Sprintf("instance %s was terminated (status %s) after %d recreations, giving up", instanceKey.Name, status, s.scope.Preemptions())
+		s.scope.SetFailure(infrav1.PreemptionRetriesExceededReason, message)
+		return errors.New(message)
+	}
+
+	if instance != nil {
+		s.Log.V(1).Info("Deleting terminated instance", "name", instanceKey.Name, "zone", instanceKey.Zone, "status", status)
+		op, err := s.instanceOperations.Delete(s.scope.Project(), instanceKey.Zone, instanceKey.Name).Context(ctx).Do()
+		if err != nil && !gcperrors.IsNotFound(err) {
+			s.Log.Error(err, "Error deleting terminated instance", "name", instanceKey.Name)
+			return err
+		}
+		if op != nil {
+			if err := s.operations.Track(deleteTarget, op); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.scope.RecordPreemption()
+	s.scope.ResetSerialOutputOffset()
+	s.scope.MarkInstanceNotReady(infrav1.WaitingForInstanceRunningReason, fmt.Sprintf("instance %s is being recreated", instanceKey.Name))
+	return errors.Wrapf(ErrInstancePreempted, "instance %s terminated with status %s, recreating it (%d/%d)",
+		instanceKey.Name, status, s.scope.Preemptions(), s.scope.MaxPreemptionRetries())
+}
+
+// insertError returns a not found error of the creation of the instance as the error of a missing resource
+// referenced in the spec, as the instance only refers to resources of the spec, e.g. the machine type.
+func insertError(instanceName string, err error) error {
+	if !gcperrors.IsNotFound(err) {
+		return err
+	}
+
+	return gcperrors.NewMissingResourceError(fmt.Sprintf("machine type or another resource referenced by instance %s", instanceName), err)
+}
+
+// isTerminated returns true if the instance was stopped or is being stopped.
+func isTerminated(instance *compute.Instance) bool {
+	switch infrav1.InstanceStatus(instance.Status) {
+	case infrav1.InstanceStatusStopping, infrav1.InstanceStatusTerminated:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// consoleSerialPort is the serial port the boot and cloud-init logs are written to.
+const consoleSerialPort = 1
+
+// captureSerialOutput stores the serial port output written since the last capture, at most every
+// serialOutputInterval unless forced. Failures are logged only, they must not fail the build.
+func (s *Service) captureSerialOutput(ctx context.Context, instance *compute.Instance, force bool) {
+	if !force && !s.scope.SerialOutputCaptureDue() {
+		return
+	}
+
+	output, err := s.serialPort.GetSerialPortOutput(s.scope.Project(), s.scope.Zone(), instance.Name).
+		Port(consoleSerialPort).Start(s.scope.SerialOutputOffset()).Context(ctx).Do()
+	if err != nil {
+		s.Log.Error(err, "Error reading serial port output", "instance", instance.Name)
+		return
+	}
+	if output.Start > s.scope.SerialOutputOffset() {
+		s.Log.V(1).Info("Serial port output was overwritten before being captured", "instance", instance.Name,
+			"lostBytes", output.Start-s.scope.SerialOutputOffset())
+	}
+
+	if err := s.scope.AppendSerialOutput(ctx, output.Contents, output.Next); err != nil {
+		s.Log.Error(err, "Error storing serial port output", "instance", instance.Name)
+	}
+}
+
+// CaptureSerialOutput stores the serial port output of the builder instance written since the last capture,
+// before the build fails and the instance is deleted. Failures are logged only.
+func (s *Service) CaptureSerialOutput(ctx context.Context) {
+	instanceName := s.scope.InstanceSpec(s.Log).Name
+	instance, err := s.instances.Get(ctx, meta.ZonalKey(instanceName, s.scope.Zone()))
+	if err != nil {
+		if !gcperrors.IsNotFound(err) {
+			s.Log.Error(err, "Error looking for instance to capture its serial port output", "name", instanceName)
+		}
+		return
+	}
+
+	s.captureSerialOutput(ctx, instance, true)
+}
@@ -18,22 +18,47 @@ package instances
 
 import (
 	"context"
+	"time"
 
 	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/go-logr/logr"
 	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
 
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
 )
 
 const ServiceName = "instance-reconciler"
 
 type instancesInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Instance, error)
-	Insert(ctx context.Context, key *meta.Key, obj *compute.Instance, options ...k8scloud.Option) error
-	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type zonesInterface interface {
+	List(ctx context.Context, fl *filter.F, options ...k8scloud.Option) ([]*compute.Zone, error)
+}
+
+type instanceOperationsInterface interface {
+	Insert(project string, zone string, instance *compute.Instance) *compute.InstancesInsertCall
+	Delete(project string, zone string, instance string) *compute.InstancesDeleteCall
+}
+
+type serialPortInterface interface {
+	GetSerialPortOutput(project string, zone string, instance string) *compute.InstancesGetSerialPortOutputCall
+}
+
+type guestAttributesInterface interface {
+	GetGuestAttributes(project string, zone string, instance string) *compute.InstancesGetGuestAttributesCall
+}
+
+type imagesInterface interface {
+	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Image, error)
+	GetFromFamily(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Image, error)
+	List(ctx context.Context, fl *filter.F, options ...k8scloud.Option) ([]*compute.Image, error)
 }
 
 type instancegroupsInterface interface {
@@ -45,16 +70,54 @@ type instancegroupsInterface interface {
 // Scope is an interfaces that hold used methods.
 type Scope interface {
 	cloud.Build
+	operations.Scope
 	InstanceSpec(log logr.Logger) *compute.Instance
 	InstanceImageSpec() *compute.AttachedDisk
+	GetInstanceID() *string
+	IsProvisionerReady() bool
+	GetComputeService() *compute.Service
+	Preemptions() int32
+	MaxPreemptionRetries() int32
+	RecordPreemption()
+	SetFailure(reason, message string)
+	FailureDomains() []string
+	ExhaustedZones() []string
+	MoveToZone(zone string)
+	ResetZone()
+	ClearExhaustedZones()
+	SourceImageReference() (string, bool)
+	SourceImageFilter() *infrav1.SourceImageFilter
+	SourceBuildRef() *corev1.LocalObjectReference
+	SourceImage() *infrav1.SourceImageStatus
+	SetSourceImage(image *compute.Image)
+	MarkSourceImageUnresolved(reason, message string)
+	InstanceHost(instance *compute.Instance) string
+	SerialOutputOffset() int64
+	ResetSerialOutputOffset()
+	SerialOutputCaptureDue() bool
+	AppendSerialOutput(ctx context.Context, contents string, next int64) error
+	ReadinessGuestAttribute() string
+	ReadinessTimeout() time.Duration
+	IsInstanceReady() bool
+	MarkInstanceReady()
+	MarkInstanceNotReady(reason, message string)
+	IsWindows() bool
+	WindowsKeyModulus() string
+	SetWindowsPassword(encryptedPassword string) error
 }
 
 // Service implements instances reconciler.
 type Service struct {
-	scope          Scope
-	instances      instancesInterface
-	instancegroups instancegroupsInterface
-	Log            logr.Logger
+	scope              Scope
+	instances          instancesInterface
+	instanceOperations instanceOperationsInterface
+	operations         *operations.Tracker
+	zones              zonesInterface
+	instancegroups     instancegroupsInterface
+	images             imagesInterface
+	serialPort         serialPortInterface
+	guestAttributes    guestAttributesInterface
+	Log                logr.Logger
 }
 
 var _ cloud.Reconciler = &Service{}
@@ -62,9 +125,15 @@ var _ cloud.Reconciler = &Service{}
 // New returns Service from given scope.
 func New(scope Scope) *Service {
 	return &Service{
-		scope:          scope,
-		instances:      scope.Cloud().Instances(),
-		instancegroups: scope.Cloud().InstanceGroups(),
-		Log:            scope.Log(ServiceName),
+		scope:              scope,
+		instances:          scope.Cloud().Instances(),
+		instanceOperations: compute.NewInstancesService(scope.GetComputeService()),
+		operations:         operations.New(scope),
+		zones:              scope.Cloud().Zones(),
+		instancegroups:     scope.Cloud().InstanceGroups(),
+		images:             scope.Cloud().Images(),
+		serialPort:         compute.NewInstancesService(scope.GetComputeService()),
+		guestAttributes:    compute.NewInstancesService(scope.GetComputeService()),
+		Log:                scope.Log(ServiceName),
 	}
 }
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	k8scloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+var (
+	// imageReferenceRegex matches [projects/<project>/]global/images/[family/]<name>.
+	imageReferenceRegex = regexp.MustCompile(`^(?:projects/([^/]+)/)?global/images/(family/)?([^/]+)$`)
+	// imageNameRegex matches a bare image or image family name.
+	imageNameRegex = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+// imageReference is a parsed reference to an image or an image family.
+type imageReference struct {
+	project string
+	name    string
+	family  bool
+}
+
+// resolveSourceImage resolves the image the instance is created from, and pins it for the rest of the build
+// so that an image family is resolved only once.
+func (s *Service) resolveSourceImage(ctx context.Context) error {
+	if s.scope.SourceImage() != nil {
+		return nil
+	}
+
+	rawRef, family := s.scope.SourceImageReference()
+	if rawRef == "" && s.scope.SourceBuildRef() != nil {
+		return errors.Errorf("source build %s is not ready", s.scope.SourceBuildRef().Name)
+	}
+	if rawRef == "" && s.scope.SourceImageFilter() != nil {
+		return s.resolveSourceImageFilter(ctx, s.scope.SourceImageFilter())
+	}
+	if rawRef == "" {
+		err := errors.New("no source image, one of spec.image, spec.imageFamily, spec.sourceBuildRef or spec.sourceImageFilter is required")
+		s.scope.MarkSourceImageUnresolved(infrav1.SourceImageNotSpecifiedReason, err.Error())
+		return err
+	}
+
+	ref, err := parseImageReference(rawRef, s.scope.Project(), family)
+	if err != nil {
+		s.scope.MarkSourceImageUnresolved(infrav1.SourceImageInvalidReason, err.Error())
+		return err
+	}
+
+	var image *compute.Image
+	if ref.family {
+		s.Log.V(1).Info("Resolving source image family", "project", ref.project, "family", ref.name)
+		image, err = s.images.GetFromFamily(ctx, meta.GlobalKey(ref.name), k8scloud.ForceProjectID(ref.project))
+	} else {
+		s.Log.V(1).Info("Resolving source image", "project", ref.project, "image", ref.name)
+		image, err = s.images.Get(ctx, meta.GlobalKey(ref.name), k8scloud.ForceProjectID(ref.project))
+	}
+	if err != nil {
+		if gcperrors.IsNotFound(err) {
+			err = gcperrors.NewMissingResourceError(fmt.Sprintf("source image %s", rawRef), err)
+			s.scope.MarkSourceImageUnresolved(infrav1.SourceImageNotFoundReason, err.Error())
+			return err
+		}
+
+		s.scope.MarkSourceImageUnresolved(infrav1.SourceImageResolutionFailedReason, err.Error())
+		return errors.Wrapf(err, "failed to resolve source image %s", rawRef)
+	}
+
+	s.Log.Info("Resolved source image", "reference", rawRef, "image", image.SelfLink)
+	s.scope.SetSourceImage(image)
+	return nil
+}
+
+// resolveSourceImageFilter resolves the source image to the newest ready image matching the given filter.
+func (s *Service) resolveSourceImageFilter(ctx context.Context, imageFilter *infrav1.SourceImageFilter) error {
+	project := ptr.Deref(imageFilter.Project, s.scope.Project())
+
+	fl := filter.Regexp("status", "READY").And(imageFilter.Labels.ToComputeFilter())
+	if imageFilter.NameRegex != nil {
+		fl = fl.AndRegexp("name", *imageFilter.NameRegex)
+	}
+	if imageFilter.Architecture != nil {
+		fl = fl.AndRegexp("architecture", *imageFilter.Architecture)
+	}
+
+	s.Log.V(1).Info("Resolving source image filter", "project", project, "filter", fl.String())
+	candidates, err := s.images.List(ctx, fl, k8scloud.ForceProjectID(project))
+	if err != nil {
+		s.scope.MarkSourceImageUnresolved(infrav1.SourceImageResolutionFailedReason, err.Error())
+		return errors.Wrapf(err, "failed to list source images in project %s", project)
+	}
+
+	var newest *compute.Image
+	for _, candidate := range candidates {
+		if candidate.Deprecated != nil && candidate.Deprecated.State != "" && candidate.Deprecated.State != "ACTIVE" {
+			continue
+		}
+		// RFC 3339 timestamps sort chronologically.
+		if newest == nil || candidate.CreationTimestamp > newest.CreationTimestamp {
+			newest = candidate
+		}
+	}
+	if newest == nil {
+		err := errors.Errorf("no image in project %s matches the source image filter %q", project, fl.String())
+		s.scope.MarkSourceImageUnresolved(infrav1.SourceImageNotFoundReason, err.Error())
+		return err
+	}
+
+	s.Log.Info("Resolved source image filter", "image", newest.SelfLink)
+	s.scope.SetSourceImage(newest)
+	return nil
+}
+
+// parseImageReference parses an image or image family reference, as a full URL, a partial URL or a bare name.
+// A bare name refers to an image, or an image family if family is true, in the default project.
+func parseImageReference(ref, defaultProject string, family bool) (*imageReference, error) {
+	trimmed := ref
+	if i := strings.Index(trimmed, "projects/"); strings.HasPrefix(trimmed, "https://") && i >= 0 {
+		trimmed = trimmed[i:]
+	}
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	if imageNameRegex.MatchString(trimmed) {
+		return &imageReference{project: defaultProject, name: trimmed, family: family}, nil
+	}
+
+	matches := imageReferenceRegex.FindStringSubmatch(trimmed)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid source image reference %q", ref)
+	}
+
+	parsed := &imageReference{project: matches[1], name: matches[3], family: matches[2] != ""}
+	if parsed.project == "" {
+		parsed.project = defaultProject
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		family  bool
+		want    *imageReference
+		wantErr bool
+	}{
+		{
+			name: "bare image name",
+			ref:  "ubuntu-2204-jammy-v20240101",
+			want: &imageReference{project: "my-project", name: "ubuntu-2204-jammy-v20240101"},
+		},
+		{
+			name:   "bare family name",
+			ref:    "ubuntu-2204-lts",
+			family: true,
+			want:   &imageReference{project: "my-project", name: "ubuntu-2204-lts", family: true},
+		},
+		{
+			name: "partial image URL",
+			ref:  "projects/ubuntu-os-cloud/global/images/ubuntu-2204-jammy-v20240101",
+			want: &imageReference{project: "ubuntu-os-cloud", name: "ubuntu-2204-jammy-v20240101"},
+		},
+		{
+			name: "partial family URL",
+			ref:  "projects/ubuntu-os-cloud/global/images/family/ubuntu-2204-lts",
+			want: &imageReference{project: "ubuntu-os-cloud", name: "ubuntu-2204-lts", family: true},
+		},
+		{
+			name:   "partial URL overrides the family flag",
+			ref:    "projects/ubuntu-os-cloud/global/images/ubuntu-2204-jammy-v20240101",
+			family: true,
+			want:   &imageReference{project: "ubuntu-os-cloud", name: "ubuntu-2204-jammy-v20240101"},
+		},
+		{
+			name: "partial URL without project",
+			ref:  "global/images/family/debian-12",
+			want: &imageReference{project: "my-project", name: "debian-12", family: true},
+		},
+		{
+			name: "partial URL with a leading slash",
+			ref:  "/projects/debian-cloud/global/images/debian-12-bookworm-v20240110",
+			want: &imageReference{project: "debian-cloud", name: "debian-12-bookworm-v20240110"},
+		},
+		{
+			name: "full image URL",
+			ref:  "https://www.googleapis.com/compute/v1/projects/debian-cloud/global/images/debian-12-bookworm-v20240110",
+			want: &imageReference{project: "debian-cloud", name: "debian-12-bookworm-v20240110"},
+		},
+		{
+			name: "full family URL",
+			ref:  "https://compute.googleapis.com/compute/v1/projects/debian-cloud/global/images/family/debian-12",
+			want: &imageReference{project: "debian-cloud", name: "debian-12", family: true},
+		},
+		{
+			name:    "full URL without project",
+			ref:     "https://www.googleapis.com/compute/v1/global/images/debian-12",
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			ref:     "Debian_12",
+			wantErr: true,
+		},
+		{
+			name:    "other collection",
+			ref:     "projects/debian-cloud/global/snapshots/debian-12",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			ref:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ref, err := parseImageReference(tt.ref, "my-project", tt.family)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ref).To(Equal(tt.want))
+		})
+	}
+}
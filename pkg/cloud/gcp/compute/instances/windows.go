@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+)
+
+// windowsKeysSerialPort is the serial port the guest agent reports the encrypted password on.
+const windowsKeysSerialPort = 4
+
+// windowsKeyResponse is the response of the guest agent to a windows-keys metadata entry.
+type windowsKeyResponse struct {
+	Modulus           string `json:"modulus"`
+	EncryptedPassword string `json:"encryptedPassword"`
+	ErrorMessage      string `json:"errorMessage"`
+}
+
+// reconcileWindowsPassword reads the password set by the guest agent for the windows key of the build.
+// It returns true once the password is available.
+func (s *Service) reconcileWindowsPassword(ctx context.Context, instance *compute.Instance) (bool, error) {
+	output, err := s.serialPort.GetSerialPortOutput(s.scope.Project(), s.scope.Zone(), instance.Name).Port(windowsKeysSerialPort).Context(ctx).Do()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read the windows password from the serial port")
+	}
+
+	modulus := s.scope.WindowsKeyModulus()
+	// The guest agent writes one JSON object per line, the latest response wins.
+	lines := strings.Split(output.Contents, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		response := &windowsKeyResponse{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(lines[i])), response); err != nil || response.Modulus != modulus {
+			continue
+		}
+		if response.ErrorMessage != "" {
+			return false, errors.Errorf("guest agent failed to set the windows password: %s", response.ErrorMessage)
+		}
+
+		return true, s.scope.SetWindowsPassword(response.EncryptedPassword)
+	}
+
+	return false, nil
+}
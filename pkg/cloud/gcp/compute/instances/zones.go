@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// ErrZoneResourcesExhausted is returned when the zone had not enough resources to create the builder instance
+// and the instance is created in the next zone on the next reconcile.
+var ErrZoneResourcesExhausted = errors.New("zone resources exhausted")
+
+// fallbackZone moves the builder instance to the next zone of the failure domains, or of the region if none
+// are set, when err reports that the current zone has not enough resources. Other errors are returned as is.
+// Once every zone was tried, it starts over from spec.zone and returns err so that the creation is retried
+// with a backoff.
+func (s *Service) fallbackZone(ctx context.Context, err error) error {
+	if !gcperrors.IsResourceExhausted(err) {
+		return err
+	}
+
+	zone := s.scope.Zone()
+	zones, lerr := s.candidateZones(ctx)
+	if lerr != nil {
+		return lerr
+	}
+
+	exhausted := sets.New(s.scope.ExhaustedZones()...).Insert(zone)
+	for _, next := range zones {
+		if exhausted.Has(next) {
+			continue
+		}
+
+		s.Log.Info("Zone has not enough resources, moving the instance to the next zone", "zone", zone, "next", next)
+		s.scope.MoveToZone(next)
+		return errors.Wrapf(ErrZoneResourcesExhausted, "zone %s has not enough resources (%v), creating the instance in zone %s", zone, err, next)
+	}
+
+	s.scope.ResetZone()
+	return errors.Wrapf(err, "none of the zones %v has enough resources", sets.List(exhausted))
+}
+
+// candidateZones returns the zones the builder instance can be created in.
+func (s *Service) candidateZones(ctx context.Context) ([]string, error) {
+	if domains := s.scope.FailureDomains(); len(domains) > 0 {
+		return domains, nil
+	}
+
+	regionZones, err := s.zones.List(ctx, filter.Regexp("region", fmt.Sprintf(".*/regions/%s", s.scope.Region())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the zones of region %s: %w", s.scope.Region(), err)
+	}
+
+	zones := make([]string, 0, len(regionZones))
+	for _, zone := range regionZones {
+		if zone.Status == "UP" {
+			zones = append(zones, zone.Name)
+		}
+	}
+	sort.Strings(zones)
+
+	return zones, nil
+}
@@ -18,14 +18,20 @@ package networks
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/pkg/errors"
 	"google.golang.org/api/compute/v1"
 
 	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
-	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1alpha1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
 )
 
 // Reconcile reconcile cluster network components.
@@ -33,12 +39,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	s.Log.Info("Reconciling network resources")
 	network, err := s.createOrGetNetwork(ctx)
 	if err != nil {
+		s.markNotReady(err)
 		return err
 	}
 
 	if network.Description == infrav1.ClusterTagKey(s.scope.Name()) {
 		router, err := s.createOrGetRouter(ctx, network)
 		if err != nil {
+			s.markNotReady(err)
 			return err
 		}
 
@@ -46,9 +54,20 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	}
 
 	s.scope.Network().SelfLink = ptr.To[string](network.SelfLink)
+	s.scope.MarkConditionTrue(infrav1alpha1.NetworkReadyCondition)
 	return nil
 }
 
+// markNotReady marks the network as not ready, either because it is being created or because of err.
+func (s *Service) markNotReady(err error) {
+	if errors.Is(err, operations.ErrInProgress) {
+		s.scope.MarkConditionFalse(infrav1alpha1.NetworkReadyCondition, infrav1alpha1.NetworkCreatingReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return
+	}
+
+	s.scope.MarkConditionFalse(infrav1alpha1.NetworkReadyCondition, infrav1alpha1.NetworkReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+}
+
 // Delete delete cluster network components.
 func (s *Service) Delete(ctx context.Context) error {
 	if s.scope.IsSharedVpc() {
@@ -58,20 +77,45 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 	s.Log.Info("Deleting network resources")
-	networkKey := meta.GlobalKey(s.scope.NetworkName())
+	networkName := s.scope.NetworkName()
+	networkTarget := operations.Target("delete", "networks", networkName)
+	done, err := s.operations.Poll(ctx, networkTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(operations.ErrInProgress, "network %s is being deleted", networkName)
+	}
+
+	networkKey := meta.GlobalKey(networkName)
 	s.Log.V(1).Info("Looking for network before deleting", "name", networkKey)
 	network, err := s.networks.Get(ctx, networkKey)
 	if err != nil {
-		return gcperrors.IgnoreNotFound(err)
+		if !gcperrors.IsNotFound(err) {
+			return err
+		}
+
+		s.scope.Network().Router = nil
+		s.scope.Network().SelfLink = nil
+		return nil
 	}
 
 	if network.Description != infrav1.ClusterTagKey(s.scope.Name()) {
 		return nil
 	}
 
-	s.Log.V(1).Info("Found network created by capg", "name", s.scope.NetworkName())
+	s.Log.V(1).Info("Found network created by capg", "name", networkName)
 
 	routerSpec := s.scope.NatRouterSpec()
+	routerTarget := operations.Target("delete", "routers", routerSpec.Name)
+	done, err = s.operations.Poll(ctx, routerTarget)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.Wrapf(operations.ErrInProgress, "cloudnat router %s is being deleted", routerSpec.Name)
+	}
+
 	routerKey := meta.RegionalKey(routerSpec.Name, s.scope.Region())
 	s.Log.V(1).Info("Looking for cloudnat router before deleting", "name", routerSpec.Name)
 	router, err := s.routers.Get(ctx, routerKey)
@@ -80,55 +124,93 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	if router != nil && router.Description == infrav1.ClusterTagKey(s.scope.Name()) {
-		if err := s.routers.Delete(ctx, routerKey); err != nil && !gcperrors.IsNotFound(err) {
+		op, err := s.routerOperations.Delete(s.scope.Project(), s.scope.Region(), routerSpec.Name).Context(ctx).Do()
+		if err != nil && !gcperrors.IsNotFound(err) {
 			return err
 		}
+		if err == nil {
+			if err := s.operations.Track(routerTarget, op); err != nil {
+				return err
+			}
+
+			return errors.Wrapf(operations.ErrInProgress, "cloudnat router %s is being deleted", routerSpec.Name)
+		}
 	}
 
-	if err := s.networks.Delete(ctx, networkKey); err != nil {
-		s.Log.Error(err, "Error deleting a network", "name", s.scope.NetworkName())
+	op, err := s.networkOperations.Delete(s.scope.Project(), networkName).Context(ctx).Do()
+	if err != nil {
+		if gcperrors.IsNotFound(err) {
+			s.scope.Network().Router = nil
+			s.scope.Network().SelfLink = nil
+			return nil
+		}
+
+		s.Log.Error(err, "Error deleting a network", "name", networkName)
+		return err
+	}
+	if err := s.operations.Track(networkTarget, op); err != nil {
 		return err
 	}
 
-	s.scope.Network().Router = nil
-	s.scope.Network().SelfLink = nil
-	return nil
+	return errors.Wrapf(operations.ErrInProgress, "network %s is being deleted", networkName)
 }
 
 // createOrGetNetwork creates a network if not exist otherwise return existing network.
+// It returns an error wrapping operations.ErrInProgress while the network is being created.
 func (s *Service) createOrGetNetwork(ctx context.Context) (*compute.Network, error) {
-	s.Log.V(1).Info("Looking for network", "name", s.scope.NetworkName())
-	networkKey := meta.GlobalKey(s.scope.NetworkName())
+	networkName := s.scope.NetworkName()
+	insertTarget := operations.Target("insert", "networks", networkName)
+	done, err := s.operations.Poll(ctx, insertTarget)
+	if err != nil {
+		return nil, err
+	}
+	if !done {
+		return nil, errors.Wrapf(operations.ErrInProgress, "network %s is being created", networkName)
+	}
+
+	s.Log.V(1).Info("Looking for network", "name", networkName)
+	networkKey := meta.GlobalKey(networkName)
 	network, err := s.networks.Get(ctx, networkKey)
 	if err != nil {
 		if !gcperrors.IsNotFound(err) {
-			s.Log.Error(err, "Error looking for network", "name", s.scope.NetworkName())
+			s.Log.Error(err, "Error looking for network", "name", networkName)
 			return nil, err
 		}
 
 		if s.scope.IsSharedVpc() {
-			s.Log.Error(err, "Shared VPC is enabled, but could not find existing network", "name", s.scope.NetworkName())
-			return nil, err
+			s.Log.Error(err, "Shared VPC is enabled, but could not find existing network", "name", networkName)
+			return nil, gcperrors.NewMissingResourceError(fmt.Sprintf("network %s", networkName), err)
 		}
 
-		s.Log.V(1).Info("Creating a network", "name", s.scope.NetworkName())
-		if err := s.networks.Insert(ctx, networkKey, s.scope.NetworkSpec()); err != nil {
-			s.Log.Error(err, "Error creating a network", "name", s.scope.NetworkName())
+		s.Log.V(1).Info("Creating a network", "name", networkName)
+		op, err := s.networkOperations.Insert(s.scope.Project(), s.scope.NetworkSpec()).Context(ctx).Do()
+		if err != nil {
+			s.Log.Error(err, "Error creating a network", "name", networkName)
 			return nil, err
 		}
-
-		network, err = s.networks.Get(ctx, networkKey)
-		if err != nil {
+		if err := s.operations.Track(insertTarget, op); err != nil {
 			return nil, err
 		}
+
+		return nil, errors.Wrapf(operations.ErrInProgress, "network %s is being created", networkName)
 	}
 
 	return network, nil
 }
 
 // createOrGetRouter creates a cloudnat router if not exist otherwise return the existing.
+// It returns an error wrapping operations.ErrInProgress while the router is being created.
 func (s *Service) createOrGetRouter(ctx context.Context, network *compute.Network) (*compute.Router, error) {
 	spec := s.scope.NatRouterSpec()
+	insertTarget := operations.Target("insert", "routers", spec.Name)
+	done, err := s.operations.Poll(ctx, insertTarget)
+	if err != nil {
+		return nil, err
+	}
+	if !done {
+		return nil, errors.Wrapf(operations.ErrInProgress, "cloudnat router %s is being created", spec.Name)
+	}
+
 	s.Log.V(1).Info("Looking for cloudnat router", "name", spec.Name)
 	routerKey := meta.RegionalKey(spec.Name, s.scope.Region())
 	router, err := s.routers.Get(ctx, routerKey)
@@ -140,21 +222,22 @@ func (s *Service) createOrGetRouter(ctx context.Context, network *compute.Networ
 
 		if s.scope.IsSharedVpc() {
 			s.Log.Error(err, "Shared VPC is enabled, but could not find existing router", "name", routerKey)
-			return nil, err
+			return nil, gcperrors.NewMissingResourceError(fmt.Sprintf("router %s", spec.Name), err)
 		}
 
 		spec.Network = network.SelfLink
 		spec.Description = infrav1.ClusterTagKey(s.scope.Name())
 		s.Log.V(1).Info("Creating a cloudnat router", "name", spec.Name)
-		if err := s.routers.Insert(ctx, routerKey, spec); err != nil {
+		op, err := s.routerOperations.Insert(s.scope.Project(), s.scope.Region(), spec).Context(ctx).Do()
+		if err != nil {
 			s.Log.Error(err, "Error creating a cloudnat router", "name", spec.Name)
 			return nil, err
 		}
-
-		router, err = s.routers.Get(ctx, routerKey)
-		if err != nil {
+		if err := s.operations.Track(insertTarget, op); err != nil {
 			return nil, err
 		}
+
+		return nil, errors.Wrapf(operations.ErrInProgress, "cloudnat router %s is being created", spec.Name)
 	}
 
 	return router, nil
@@ -24,35 +24,46 @@ import (
 	"google.golang.org/api/compute/v1"
 
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
 )
 
 const ServiceName = "networks-reconciler"
 
 type networksInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Network, error)
-	Insert(ctx context.Context, key *meta.Key, obj *compute.Network, options ...k8scloud.Option) error
-	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type networkOperationsInterface interface {
+	Insert(project string, network *compute.Network) *compute.NetworksInsertCall
+	Delete(project string, network string) *compute.NetworksDeleteCall
 }
 
 type routersInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Router, error)
-	Insert(ctx context.Context, key *meta.Key, obj *compute.Router, options ...k8scloud.Option) error
-	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type routerOperationsInterface interface {
+	Insert(project string, region string, router *compute.Router) *compute.RoutersInsertCall
+	Delete(project string, region string, router string) *compute.RoutersDeleteCall
 }
 
 // Scope is an interfaces that hold used methods.
 type Scope interface {
 	cloud.Build
+	operations.Scope
 	NetworkSpec() *compute.Network
 	NatRouterSpec() *compute.Router
 }
 
 // Service implements networks reconciler.
 type Service struct {
-	scope    Scope
-	networks networksInterface
-	routers  routersInterface
-	Log      logr.Logger
+	scope             Scope
+	networks          networksInterface
+	networkOperations networkOperationsInterface
+	routers           routersInterface
+	routerOperations  routerOperationsInterface
+	operations        *operations.Tracker
+	Log               logr.Logger
 }
 
 var _ cloud.Reconciler = &Service{}
@@ -64,10 +75,15 @@ func New(scope Scope) *Service {
 		scopeCloud = scope.NetworkCloud()
 	}
 
+	// Networks and routers are only created and deleted in the project of the build, never in the
+	// project of a shared VPC.
 	return &Service{
-		scope:    scope,
-		networks: scopeCloud.Networks(),
-		routers:  scopeCloud.Routers(),
-		Log:      scope.Log(ServiceName),
+		scope:             scope,
+		networks:          scopeCloud.Networks(),
+		networkOperations: compute.NewNetworksService(scope.GetComputeService()),
+		routers:           scopeCloud.Routers(),
+		routerOperations:  compute.NewRoutersService(scope.GetComputeService()),
+		operations:        operations.New(scope),
+		Log:               scope.Log(ServiceName),
 	}
 }
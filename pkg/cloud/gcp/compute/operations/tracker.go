@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// ErrInProgress is returned while an operation the caller depends on is running.
+var ErrInProgress = errors.New("operation in progress")
+
+// errorStatusCodes maps the codes of the errors of failed operations to the HTTP status of the equivalent
+// synchronous errors, for the operations that do not report it.
+var errorStatusCodes = map[string]int{
+	"INVALID_FIELD_VALUE":     http.StatusBadRequest,
+	"INVALID_USAGE":           http.StatusBadRequest,
+	"BAD_REQUEST":             http.StatusBadRequest,
+	"PERMISSION_DENIED":       http.StatusForbidden,
+	"PERMISSIONS_ERROR":       http.StatusForbidden,
+	"QUOTA_EXCEEDED":          http.StatusForbidden,
+	"RESOURCE_NOT_FOUND":      http.StatusNotFound,
+	"NOT_FOUND":               http.StatusNotFound,
+	"RESOURCE_ALREADY_EXISTS": http.StatusConflict,
+	"ALREADY_EXISTS":          http.StatusConflict,
+	"RATE_LIMIT_EXCEEDED":     http.StatusTooManyRequests,
+}
+
+type zoneOperationsInterface interface {
+	Get(project string, zone string, operation string) *compute.ZoneOperationsGetCall
+}
+
+type regionOperationsInterface interface {
+	Get(project string, region string, operation string) *compute.RegionOperationsGetCall
+}
+
+type globalOperationsInterface interface {
+	Get(project string, operation string) *compute.GlobalOperationsGetCall
+}
+
+// Scope is an interface that holds methods used for tracking operations.
+type Scope interface {
+	Project() string
+	GetComputeService() *compute.Service
+	Operation(target string) *infrav1.OperationStatus
+	Operations() []infrav1.OperationStatus
+	SetOperation(target string, op *compute.Operation)
+	ClearOperation(target string)
+}
+
+// Tracker records the operations started by the reconcilers in the status, and polls them on later
+// reconciles instead of blocking until they are done.
+type Tracker struct {
+	scope            Scope
+	zoneOperations   zoneOperationsInterface
+	regionOperations regionOperationsInterface
+	globalOperations globalOperationsInterface
+}
+
+// New returns a Tracker from given scope.
+func New(scope Scope) *Tracker {
+	return NewForService(scope, scope.GetComputeService())
+}
+
+// NewForService returns a Tracker polling the operations with the given compute service, e.g. one using the
+// credentials of another project. The operations are still recorded in the status of the scope.
+func NewForService(scope Scope, computeSvc *compute.Service) *Tracker {
+	return &Tracker{
+		scope:            scope,
+		zoneOperations:   compute.NewZoneOperationsService(computeSvc),
+		regionOperations: compute.NewRegionOperationsService(computeSvc),
+		globalOperations: compute.NewGlobalOperationsService(computeSvc),
+	}
+}
+
+// Target returns the target of an action on a resource, e.g. "insert instances/forge-build".
+func Target(action, collection, name string) string {
+	return fmt.Sprintf("%s %s/%s", action, collection, name)
+}
+
+// Targets returns the targets of the running operations of an action on a collection.
+func (t *Tracker) Targets(action, collection string) []string {
+	prefix := fmt.Sprintf("%s %s/", action, collection)
+	var targets []string
+	for _, op := range t.scope.Operations() {
+		if strings.HasPrefix(op.Target, prefix) {
+			targets = append(targets, op.Target)
+		}
+	}
+
+	return targets
+}
+
+// Track records the operation started for the target.
+func (t *Tracker) Track(target string, op *compute.Operation) error {
+	if op.Status == "DONE" {
+		return operationError(op)
+	}
+	t.scope.SetOperation(target, op)
+
+	return nil
+}
+
+// Poll returns true once no operation of the target is running, and the error of the operation if it failed.
+func (t *Tracker) Poll(ctx context.Context, target string) (bool, error) {
+	status := t.scope.Operation(target)
+	if status == nil {
+		return true, nil
+	}
+
+	project := status.Project
+	if project == "" {
+		project = t.scope.Project()
+	}
+
+	var op *compute.Operation
+	var err error
+	switch {
+	case status.Zone != "":
+		op, err = t.zoneOperations.Get(project, status.Zone, status.Name).Context(ctx).Do()
+	case status.Region != "":
+		op, err = t.regionOperations.Get(project, status.Region, status.Name).Context(ctx).Do()
+	default:
+		op, err = t.globalOperations.Get(project, status.Name).Context(ctx).Do()
+	}
+	if gcperrors.IsNotFound(err) {
+		// Operations are garbage collected some time after they are done, the state of the resource tells the outcome.
+		t.scope.ClearOperation(target)
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get operation %s: %w", status.Name, err)
+	}
+	if op.Status != "DONE" {
+		return false, nil
+	}
+
+	t.scope.ClearOperation(target)
+	if err := operationError(op); err != nil {
+		return true, fmt.Errorf("operation %s failed: %w", target, err)
+	}
+
+	return true, nil
+}
+
+// operationError returns the error of a failed operation as a Google API error, with the codes of the
+// operation errors as reasons, so that it is classified like the errors of synchronous calls.
+func operationError(op *compute.Operation) error {
+	if op.Error == nil {
+		return nil
+	}
+
+	ae := &googleapi.Error{Code: int(op.HttpErrorStatusCode)}
+	for _, e := range op.Error.Errors {
+		if e == nil {
+			continue
+		}
+		ae.Errors = append(ae.Errors, googleapi.ErrorItem{Reason: e.Code, Message: e.Message})
+	}
+	if len(ae.Errors) == 0 {
+		return nil
+	}
+
+	ae.Message = ae.Errors[0].Message
+	if ae.Code == 0 {
+		ae.Code = http.StatusInternalServerError
+		if code, ok := errorStatusCodes[ae.Errors[0].Reason]; ok {
+			ae.Code = code
+		}
+	}
+
+	return ae
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// fakeScope records the operations in memory, the operations are served by a fake compute API.
+type fakeScope struct {
+	computeService *compute.Service
+	operations     []infrav1.OperationStatus
+}
+
+func (s *fakeScope) Project() string { return "my-project" }
+
+func (s *fakeScope) GetComputeService() *compute.Service { return s.computeService }
+
+func (s *fakeScope) Operation(target string) *infrav1.OperationStatus {
+	for i := range s.operations {
+		if s.operations[i].Target == target {
+			return &s.operations[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeScope) Operations() []infrav1.OperationStatus { return s.operations }
+
+func (s *fakeScope) SetOperation(target string, op *compute.Operation) {
+	s.ClearOperation(target)
+	status := infrav1.OperationStatus{Target: target, Name: op.Name}
+	if op.Zone != "" {
+		status.Zone = path.Base(op.Zone)
+	}
+	if op.Region != "" {
+		status.Region = path.Base(op.Region)
+	}
+	s.operations = append(s.operations, status)
+}
+
+func (s *fakeScope) ClearOperation(target string) {
+	operations := s.operations[:0]
+	for _, op := range s.operations {
+		if op.Target != target {
+			operations = append(operations, op)
+		}
+	}
+	s.operations = operations
+}
+
+// newFakeScope returns a scope whose compute API serves the given operations by URL path, and answers
+// with the given status code for the other paths.
+func newFakeScope(t *testing.T, ops map[string]*compute.Operation, missingCode int) *fakeScope {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, ok := ops[r.URL.Path]
+		if !ok {
+			w.WriteHeader(missingCode)
+			_, _ = w.Write([]byte(`{"error": {"message": "operation unavailable"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(op)
+	}))
+	t.Cleanup(server.Close)
+
+	computeService, err := compute.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fakeScope{computeService: computeService}
+}
+
+func TestTargets(t *testing.T) {
+	g := NewWithT(t)
+	scope := &fakeScope{}
+	tracker := &Tracker{scope: scope}
+
+	scope.SetOperation(Target("delete", "images", "jan"), &compute.Operation{Name: "op-1"})
+	scope.SetOperation(Target("deprecate", "images", "feb"), &compute.Operation{Name: "op-2"})
+	scope.SetOperation(Target("delete", "images", "dec"), &compute.Operation{Name: "op-3"})
+	scope.SetOperation(Target("delete", "machineImages", "mar"), &compute.Operation{Name: "op-4"})
+
+	g.Expect(tracker.Targets("delete", "images")).To(Equal([]string{"delete images/jan", "delete images/dec"}))
+	g.Expect(tracker.Targets("deprecate", "images")).To(Equal([]string{"deprecate images/feb"}))
+	g.Expect(tracker.Targets("insert", "images")).To(BeEmpty())
+}
+
+func TestTrack(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      *compute.Operation
+		want    *infrav1.OperationStatus
+		wantErr func(error) bool
+	}{
+		{
+			name: "running zonal operation",
+			op: &compute.Operation{
+				Name:   "op-1",
+				Status: "RUNNING",
+				Zone:   "https://www.googleapis.com/compute/v1/projects/my-project/zones/europe-west1-b",
+			},
+			want: &infrav1.OperationStatus{Target: "insert instances/my-build", Name: "op-1", Zone: "europe-west1-b"},
+		},
+		{
+			name: "pending regional operation",
+			op: &compute.Operation{
+				Name:   "op-2",
+				Status: "PENDING",
+				Region: "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1",
+			},
+			want: &infrav1.OperationStatus{Target: "insert instances/my-build", Name: "op-2", Region: "europe-west1"},
+		},
+		{
+			name: "running global operation",
+			op:   &compute.Operation{Name: "op-3", Status: "RUNNING"},
+			want: &infrav1.OperationStatus{Target: "insert instances/my-build", Name: "op-3"},
+		},
+		{
+			name: "operation done",
+			op:   &compute.Operation{Name: "op-4", Status: "DONE"},
+		},
+		{
+			name: "operation failed",
+			op: &compute.Operation{
+				Name:                "op-5",
+				Status:              "DONE",
+				HttpErrorStatusCode: http.StatusBadRequest,
+				Error: &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+					{Code: "INVALID_FIELD_VALUE", Message: "Invalid value for field 'resource.machineType'"},
+				}},
+			},
+			wantErr: gcperrors.IsInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scope := &fakeScope{}
+			tracker := &Tracker{scope: scope}
+
+			err := tracker.Track(Target("insert", "instances", "my-build"), tt.op)
+			if tt.wantErr != nil {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(tt.wantErr(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tt.want == nil {
+				g.Expect(scope.operations).To(BeEmpty())
+				return
+			}
+			g.Expect(scope.operations).To(ConsistOf(*tt.want))
+		})
+	}
+}
+
+func TestPoll(t *testing.T) {
+	const target = "insert instances/my-build"
+	failed := &compute.Operation{
+		Name:   "op-1",
+		Status: "DONE",
+		Error: &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+			{Code: "QUOTA_EXCEEDED", Message: "Quota 'CPUS' exceeded"},
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		status      *infrav1.OperationStatus
+		ops         map[string]*compute.Operation
+		missingCode int
+		wantDone    bool
+		wantErr     func(error) bool
+		wantTracked bool
+	}{
+		{
+			name:     "no operation",
+			wantDone: true,
+		},
+		{
+			name:   "zonal operation still running",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1", Zone: "europe-west1-b"},
+			ops: map[string]*compute.Operation{
+				"/projects/my-project/zones/europe-west1-b/operations/op-1": {Name: "op-1", Status: "RUNNING"},
+			},
+			wantDone:    false,
+			wantTracked: true,
+		},
+		{
+			name:   "regional operation still running",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1", Region: "europe-west1"},
+			ops: map[string]*compute.Operation{
+				"/projects/my-project/regions/europe-west1/operations/op-1": {Name: "op-1", Status: "PENDING"},
+			},
+			wantDone:    false,
+			wantTracked: true,
+		},
+		{
+			name:   "global operation finished",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1"},
+			ops: map[string]*compute.Operation{
+				"/projects/my-project/global/operations/op-1": {Name: "op-1", Status: "DONE"},
+			},
+			wantDone: true,
+		},
+		{
+			name:   "zonal operation finished",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1", Zone: "europe-west1-b"},
+			ops: map[string]*compute.Operation{
+				"/projects/my-project/zones/europe-west1-b/operations/op-1": {Name: "op-1", Status: "DONE"},
+			},
+			wantDone: true,
+		},
+		{
+			name:   "operation in another project finished",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1", Project: "other-project"},
+			ops: map[string]*compute.Operation{
+				"/projects/other-project/global/operations/op-1": {Name: "op-1", Status: "DONE"},
+			},
+			wantDone: true,
+		},
+		{
+			name:   "operation in another project still running",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1", Project: "other-project"},
+			ops: map[string]*compute.Operation{
+				"/projects/other-project/global/operations/op-1": {Name: "op-1", Status: "RUNNING"},
+				"/projects/my-project/global/operations/op-1":    {Name: "op-1", Status: "DONE"},
+			},
+			wantDone:    false,
+			wantTracked: true,
+		},
+		{
+			name:   "operation failed",
+			status: &infrav1.OperationStatus{Target: target, Name: "op-1", Zone: "europe-west1-b"},
+			ops: map[string]*compute.Operation{
+				"/projects/my-project/zones/europe-west1-b/operations/op-1": failed,
+			},
+			wantDone: true,
+			wantErr:  gcperrors.IsQuotaExceeded,
+		},
+		{
+			name:        "operation not found",
+			status:      &infrav1.OperationStatus{Target: target, Name: "op-1", Zone: "europe-west1-b"},
+			missingCode: http.StatusNotFound,
+			wantDone:    true,
+		},
+		{
+			name:        "operation unavailable",
+			status:      &infrav1.OperationStatus{Target: target, Name: "op-1", Zone: "europe-west1-b"},
+			missingCode: http.StatusServiceUnavailable,
+			wantDone:    false,
+			wantErr:     func(err error) bool { return !gcperrors.IsNotFound(err) },
+			wantTracked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scope := newFakeScope(t, tt.ops, tt.missingCode)
+			if tt.status != nil {
+				scope.operations = []infrav1.OperationStatus{*tt.status}
+			}
+			tracker := New(scope)
+
+			done, err := tracker.Poll(context.Background(), target)
+			g.Expect(done).To(Equal(tt.wantDone))
+			if tt.wantErr != nil {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(tt.wantErr(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tt.wantTracked {
+				g.Expect(scope.Operation(target)).To(Equal(tt.status))
+			} else {
+				g.Expect(scope.Operation(target)).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestOperationError(t *testing.T) {
+	tests := []struct {
+		name        string
+		op          *compute.Operation
+		wantCode    int
+		wantReasons []string
+		wantMessage string
+	}{
+		{
+			name: "reported status code",
+			op: &compute.Operation{
+				HttpErrorStatusCode: http.StatusConflict,
+				Error: &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+					{Code: "RESOURCE_ALREADY_EXISTS", Message: "The resource already exists"},
+				}},
+			},
+			wantCode:    http.StatusConflict,
+			wantReasons: []string{"RESOURCE_ALREADY_EXISTS"},
+			wantMessage: "The resource already exists",
+		},
+		{
+			name: "status code mapped from the error code",
+			op: &compute.Operation{
+				Error: &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+					{Code: "RESOURCE_NOT_FOUND", Message: "The resource was not found"},
+				}},
+			},
+			wantCode:    http.StatusNotFound,
+			wantReasons: []string{"RESOURCE_NOT_FOUND"},
+			wantMessage: "The resource was not found",
+		},
+		{
+			name: "unknown error code",
+			op: &compute.Operation{
+				Error: &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+					{Code: "ZONE_RESOURCE_POOL_EXHAUSTED", Message: "The zone does not have enough resources"},
+				}},
+			},
+			wantCode:    http.StatusInternalServerError,
+			wantReasons: []string{"ZONE_RESOURCE_POOL_EXHAUSTED"},
+			wantMessage: "The zone does not have enough resources",
+		},
+		{
+			name: "every error is a reason",
+			op: &compute.Operation{
+				HttpErrorStatusCode: http.StatusBadRequest,
+				Error: &compute.OperationError{Errors: []*compute.OperationErrorErrors{
+					{Code: "INVALID_FIELD_VALUE", Message: "Invalid value"},
+					nil,
+					{Code: "RESOURCE_NOT_READY", Message: "The resource is not ready"},
+				}},
+			},
+			wantCode:    http.StatusBadRequest,
+			wantReasons: []string{"INVALID_FIELD_VALUE", "RESOURCE_NOT_READY"},
+			wantMessage: "Invalid value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := operationError(tt.op)
+			var ae *googleapi.Error
+			g.Expect(errors.As(err, &ae)).To(BeTrue())
+			g.Expect(ae.Code).To(Equal(tt.wantCode))
+			g.Expect(ae.Message).To(Equal(tt.wantMessage))
+			reasons := make([]string, 0, len(ae.Errors))
+			for _, item := range ae.Errors {
+				reasons = append(reasons, item.Reason)
+			}
+			g.Expect(reasons).To(Equal(tt.wantReasons))
+		})
+	}
+}
+
+func TestOperationErrorWithoutErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(operationError(&compute.Operation{Status: "DONE"})).To(Succeed())
+	g.Expect(operationError(&compute.Operation{Status: "DONE", Error: &compute.OperationError{}})).To(Succeed())
+}
@@ -18,13 +18,19 @@ package subnets
 
 import (
 	"context"
+	"fmt"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/pkg/errors"
 	"google.golang.org/api/compute/v1"
 
-	"sigs.k8s.io/cluster-api-provider-gcp/cloud/gcperrors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1alpha1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
 )
 
 // Reconcile reconciles cluster network components.
@@ -33,9 +39,15 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	// reconcile subnets
 	if _, err := s.createOrGetSubnets(ctx); err != nil {
+		if errors.Is(err, operations.ErrInProgress) {
+			s.scope.MarkConditionFalse(infrav1alpha1.SubnetsReadyCondition, infrav1alpha1.SubnetsCreatingReason, clusterv1.ConditionSeverityInfo, err.Error())
+		} else {
+			s.scope.MarkConditionFalse(infrav1alpha1.SubnetsReadyCondition, infrav1alpha1.SubnetsReconciliationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		}
 		return err
 	}
 
+	s.scope.MarkConditionTrue(infrav1alpha1.SubnetsReadyCondition)
 	return nil
 }
 
@@ -46,7 +58,17 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 	for _, subnetSpec := range s.scope.SubnetSpecs() {
-		subnetKey := meta.RegionalKey(subnetSpec.Name, s.getSubnetRegion(subnetSpec))
+		deleteTarget := operations.Target("delete", "subnetworks", subnetSpec.Name)
+		done, err := s.operations.Poll(ctx, deleteTarget)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return errors.Wrapf(operations.ErrInProgress, "subnet %s is being deleted", subnetSpec.Name)
+		}
+
+		region := s.getSubnetRegion(subnetSpec)
+		subnetKey := meta.RegionalKey(subnetSpec.Name, region)
 		s.Log.V(1).Info("Looking for subnet before deleting it", "name", subnetSpec.Name)
 		subnet, err := s.subnets.Get(ctx, subnetKey)
 		if err != nil {
@@ -65,23 +87,41 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 
 		s.Log.Info("Deleting a subnet", "name", subnetSpec.Name)
-		if err := s.subnets.Delete(ctx, subnetKey); err != nil {
+		op, err := s.subnetOperations.Delete(s.scope.Project(), region, subnetSpec.Name).Context(ctx).Do()
+		if err != nil {
 			if !gcperrors.IsNotFound(err) {
 				s.Log.Error(err, "Error deleting subnet", "name", subnetSpec.Name)
 				return err
 			}
+			continue
+		}
+		if err := s.operations.Track(deleteTarget, op); err != nil {
+			return err
 		}
+
+		return errors.Wrapf(operations.ErrInProgress, "subnet %s is being deleted", subnetSpec.Name)
 	}
 
 	return nil
 }
 
 // createOrGetSubnets creates the subnetworks if they don't exist otherwise return the existing ones.
+// It returns an error wrapping operations.ErrInProgress while a subnetwork is being created.
 func (s *Service) createOrGetSubnets(ctx context.Context) ([]*compute.Subnetwork, error) {
 	subnets := []*compute.Subnetwork{}
 	for _, subnetSpec := range s.scope.SubnetSpecs() {
+		insertTarget := operations.Target("insert", "subnetworks", subnetSpec.Name)
+		done, err := s.operations.Poll(ctx, insertTarget)
+		if err != nil {
+			return subnets, err
+		}
+		if !done {
+			return subnets, errors.Wrapf(operations.ErrInProgress, "subnet %s is being created", subnetSpec.Name)
+		}
+
 		s.Log.V(1).Info("Looking for subnet", "name", subnetSpec.Name)
-		subnetKey := meta.RegionalKey(subnetSpec.Name, s.getSubnetRegion(subnetSpec))
+		region := s.getSubnetRegion(subnetSpec)
+		subnetKey := meta.RegionalKey(subnetSpec.Name, region)
 		subnet, err := s.subnets.Get(ctx, subnetKey)
 		if err != nil {
 			if !gcperrors.IsNotFound(err) {
@@ -91,21 +131,21 @@ func (s *Service) createOrGetSubnets(ctx context.Context) ([]*compute.Subnetwork
 
 			if s.scope.IsSharedVpc() {
 				s.Log.Error(err, "Shared VPC is enabled, but could not find existing subnetwork", "name", subnetSpec.Name)
-				return nil, err
+				return nil, gcperrors.NewMissingResourceError(fmt.Sprintf("subnetwork %s", subnetSpec.Name), err)
 			}
 
 			// Subnet was not found, let's create it
 			s.Log.V(1).Info("Creating a subnet", "name", subnetSpec.Name)
-			if err := s.subnets.Insert(ctx, subnetKey, subnetSpec); err != nil {
+			op, err := s.subnetOperations.Insert(s.scope.Project(), region, subnetSpec).Context(ctx).Do()
+			if err != nil {
 				s.Log.Error(err, "Error creating a subnet", "name", subnetSpec.Name)
 				return subnets, err
 			}
-
-			subnet, err = s.subnets.Get(ctx, subnetKey)
-			if err != nil {
-				s.Log.Error(err, "Error getting existing subnet", "name", subnetSpec.Name)
+			if err := s.operations.Track(insertTarget, op); err != nil {
 				return subnets, err
 			}
+
+			return subnets, errors.Wrapf(operations.ErrInProgress, "subnet %s is being created", subnetSpec.Name)
 		}
 		subnets = append(subnets, subnet)
 	}
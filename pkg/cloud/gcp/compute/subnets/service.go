@@ -25,27 +25,34 @@ import (
 	"google.golang.org/api/compute/v1"
 
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
 )
 
 const ServiceName = "subnets-reconciler"
 
 type subnetsInterface interface {
 	Get(ctx context.Context, key *meta.Key, options ...k8scloud.Option) (*compute.Subnetwork, error)
-	Insert(ctx context.Context, key *meta.Key, obj *compute.Subnetwork, options ...k8scloud.Option) error
-	Delete(ctx context.Context, key *meta.Key, options ...k8scloud.Option) error
+}
+
+type subnetOperationsInterface interface {
+	Insert(project string, region string, subnetwork *compute.Subnetwork) *compute.SubnetworksInsertCall
+	Delete(project string, region string, subnetwork string) *compute.SubnetworksDeleteCall
 }
 
 // Scope is an interfaces that hold used methods.
 type Scope interface {
 	cloud.Build
+	operations.Scope
 	SubnetSpecs() []*compute.Subnetwork
 }
 
 // Service implements subnets reconciler.
 type Service struct {
-	scope   Scope
-	subnets subnetsInterface
-	Log     logr.Logger
+	scope            Scope
+	subnets          subnetsInterface
+	subnetOperations subnetOperationsInterface
+	operations       *operations.Tracker
+	Log              logr.Logger
 }
 
 var _ cloud.Reconciler = &Service{}
@@ -57,9 +64,12 @@ func New(scope Scope) *Service {
 		cloudScope = scope.NetworkCloud()
 	}
 
+	// Subnetworks are only created and deleted in the project of the build, never in the project of a shared VPC.
 	return &Service{
-		scope:   scope,
-		subnets: cloudScope.Subnetworks(),
-		Log:     scope.Log(ServiceName),
+		scope:            scope,
+		subnets:          cloudScope.Subnetworks(),
+		subnetOperations: compute.NewSubnetworksService(scope.GetComputeService()),
+		operations:       operations.New(scope),
+		Log:              scope.Log(ServiceName),
 	}
 }
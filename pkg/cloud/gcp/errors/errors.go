@@ -18,18 +18,26 @@ limitations under the License.
 package gcperrors
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"google.golang.org/api/googleapi"
 )
 
+// Reasons of Google API errors, and codes of the errors of failed operations.
+var (
+	quotaExceededReasons     = []string{"quotaExceeded", "QUOTA_EXCEEDED"}
+	resourceExhaustedReasons = []string{"ZONE_RESOURCE_POOL_EXHAUSTED", "ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS"}
+	conflictReasons          = []string{"alreadyExists", "resourceInUseByAnotherResource", "resourceNotReady", "RESOURCE_NOT_READY"}
+	rateLimitedReasons       = []string{"rateLimitExceeded", "userRateLimitExceeded", "RATE_LIMIT_EXCEEDED"}
+	permissionDeniedReasons  = []string{"forbidden", "insufficientPermissions", "accessNotConfigured"}
+)
+
 // IsNotFound reports whether err is a Google API error
 // with http.StatusNotFround.
 func IsNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	ae, ok := err.(*googleapi.Error)
+	ae, ok := apiError(err)
 
 	return ok && ae.Code == http.StatusNotFound
 }
@@ -43,3 +51,145 @@ func IgnoreNotFound(err error) error {
 
 	return err
 }
+
+// MissingResourceError is the error of a resource referenced in the spec that does not exist,
+// e.g. the source image or the machine type.
+type MissingResourceError struct {
+	// Resource describes the missing resource.
+	Resource string
+	// Err is the not found error.
+	Err error
+}
+
+// NewMissingResourceError returns err as the error of the given resource referenced in the spec.
+// Only the callers know whether a not found error is caused by the spec or by a race, e.g. with
+// the deletion of the instance, so a not found error is only terminal once marked as such.
+func NewMissingResourceError(resource string, err error) error {
+	return &MissingResourceError{Resource: resource, Err: err}
+}
+
+func (e *MissingResourceError) Error() string {
+	return fmt.Sprintf("%s not found: %v", e.Resource, e.Err)
+}
+
+func (e *MissingResourceError) Unwrap() error {
+	return e.Err
+}
+
+// IsMissingResource reports whether err is the error of a resource referenced in the spec that does not exist.
+func IsMissingResource(err error) bool {
+	var mre *MissingResourceError
+
+	return errors.As(err, &mre)
+}
+
+// NameConflictError is the error of a resource the build creates that already exists and is not owned
+// by the build, e.g. an image of the same name created by another build.
+type NameConflictError struct {
+	// Resource describes the conflicting resource.
+	Resource string
+}
+
+// NewNameConflictError returns the error of the given resource that exists but is not owned by the build.
+func NewNameConflictError(resource string) error {
+	return &NameConflictError{Resource: resource}
+}
+
+func (e *NameConflictError) Error() string {
+	return fmt.Sprintf("%s already exists and is not owned by the build", e.Resource)
+}
+
+// IsNameConflict reports whether err is the error of a resource that exists but is not owned by the build.
+func IsNameConflict(err error) bool {
+	var nce *NameConflictError
+
+	return errors.As(err, &nce)
+}
+
+// IsQuotaExceeded reports whether err is a Google API error reporting that a quota of the project is exceeded.
+func IsQuotaExceeded(err error) bool {
+	ae, ok := apiError(err)
+
+	return ok && hasReason(ae, quotaExceededReasons...)
+}
+
+// IsResourceExhausted reports whether err is a Google API error reporting that the zone has not enough
+// resources available, e.g. for the machine type. Other exhausted resources, e.g. a rate limit, are not
+// fixed by another zone.
+func IsResourceExhausted(err error) bool {
+	ae, ok := apiError(err)
+
+	return ok && hasReason(ae, resourceExhaustedReasons...)
+}
+
+// IsRateLimited reports whether err is a Google API error reporting that too many requests were sent.
+func IsRateLimited(err error) bool {
+	ae, ok := apiError(err)
+
+	return ok && (ae.Code == http.StatusTooManyRequests && !hasReason(ae, resourceExhaustedReasons...) || hasReason(ae, rateLimitedReasons...))
+}
+
+// IsConflict reports whether err is a Google API error reporting that the resource already exists,
+// or is in use or not ready for the operation.
+func IsConflict(err error) bool {
+	ae, ok := apiError(err)
+
+	return ok && (ae.Code == http.StatusConflict || hasReason(ae, conflictReasons...))
+}
+
+// IsInvalidArgument reports whether err is a Google API error reporting an invalid request,
+// e.g. an invalid machine type.
+func IsInvalidArgument(err error) bool {
+	ae, ok := apiError(err)
+
+	return ok && ae.Code == http.StatusBadRequest && !isRetryable(err)
+}
+
+// IsPermissionDenied reports whether err is a Google API error reporting missing permissions
+// or a disabled API.
+func IsPermissionDenied(err error) bool {
+	ae, ok := apiError(err)
+	if !ok || isRetryable(err) {
+		return false
+	}
+
+	return ae.Code == http.StatusForbidden || ae.Code == http.StatusUnauthorized || hasReason(ae, permissionDeniedReasons...)
+}
+
+// IsTerminal reports whether err is an error that retrying won't fix without a change of the spec
+// or of the project configuration, e.g. an invalid argument, missing permissions, a missing resource
+// referenced in the spec or a name conflict.
+func IsTerminal(err error) bool {
+	if isRetryable(err) {
+		return false
+	}
+
+	return IsInvalidArgument(err) || IsPermissionDenied(err) || IsMissingResource(err) || IsNameConflict(err)
+}
+
+// isRetryable reports whether err is a Google API error that may succeed once retried later.
+func isRetryable(err error) bool {
+	return IsQuotaExceeded(err) || IsResourceExhausted(err) || IsRateLimited(err) || IsConflict(err)
+}
+
+func apiError(err error) (*googleapi.Error, bool) {
+	var ae *googleapi.Error
+	if err == nil || !errors.As(err, &ae) {
+		return nil, false
+	}
+
+	return ae, true
+}
+
+// hasReason reports whether one of the reasons of the error is one of the given reasons.
+func hasReason(ae *googleapi.Error, reasons ...string) bool {
+	for _, reason := range reasons {
+		for _, item := range ae.Errors {
+			if item.Reason == reason {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+)
+
+func apiErr(code int, reasons ...string) *googleapi.Error {
+	ae := &googleapi.Error{Code: code, Message: "message"}
+	for _, reason := range reasons {
+		ae.Errors = append(ae.Errors, googleapi.ErrorItem{Reason: reason, Message: "message"})
+	}
+
+	return ae
+}
+
+type classification struct {
+	notFound          bool
+	missingResource   bool
+	nameConflict      bool
+	quotaExceeded     bool
+	resourceExhausted bool
+	rateLimited       bool
+	conflict          bool
+	invalidArgument   bool
+	permissionDenied  bool
+	terminal          bool
+}
+
+func classify(err error) classification {
+	return classification{
+		notFound:          IsNotFound(err),
+		missingResource:   IsMissingResource(err),
+		nameConflict:      IsNameConflict(err),
+		quotaExceeded:     IsQuotaExceeded(err),
+		resourceExhausted: IsResourceExhausted(err),
+		rateLimited:       IsRateLimited(err),
+		conflict:          IsConflict(err),
+		invalidArgument:   IsInvalidArgument(err),
+		permissionDenied:  IsPermissionDenied(err),
+		terminal:          IsTerminal(err),
+	}
+}
+
+func TestClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want classification
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: classification{},
+		},
+		{
+			name: "not an API error",
+			err:  errors.New("boom"),
+			want: classification{},
+		},
+		{
+			name: "400 is an invalid argument",
+			err:  apiErr(http.StatusBadRequest, "invalid"),
+			want: classification{invalidArgument: true, terminal: true},
+		},
+		{
+			name: "400 not ready is a conflict",
+			err:  apiErr(http.StatusBadRequest, "resourceNotReady"),
+			want: classification{conflict: true},
+		},
+		{
+			name: "403 is a permission denied",
+			err:  apiErr(http.StatusForbidden, "forbidden"),
+			want: classification{permissionDenied: true, terminal: true},
+		},
+		{
+			name: "401 is a permission denied",
+			err:  apiErr(http.StatusUnauthorized),
+			want: classification{permissionDenied: true, terminal: true},
+		},
+		{
+			name: "403 with rateLimitExceeded is rate limited",
+			err:  apiErr(http.StatusForbidden, "rateLimitExceeded"),
+			want: classification{rateLimited: true},
+		},
+		{
+			name: "403 with quotaExceeded is a quota error",
+			err:  apiErr(http.StatusForbidden, "quotaExceeded"),
+			want: classification{quotaExceeded: true},
+		},
+		{
+			name: "429 is rate limited",
+			err:  apiErr(http.StatusTooManyRequests),
+			want: classification{rateLimited: true},
+		},
+		{
+			name: "429 with resourceExhausted is rate limited",
+			err:  apiErr(http.StatusTooManyRequests, "resourceExhausted"),
+			want: classification{rateLimited: true},
+		},
+		{
+			name: "429 with zone exhaustion and rateLimitExceeded is both",
+			err:  apiErr(http.StatusTooManyRequests, "ZONE_RESOURCE_POOL_EXHAUSTED", "rateLimitExceeded"),
+			want: classification{resourceExhausted: true, rateLimited: true},
+		},
+		{
+			name: "429 with zone exhaustion is not rate limited",
+			err:  apiErr(http.StatusTooManyRequests, "ZONE_RESOURCE_POOL_EXHAUSTED"),
+			want: classification{resourceExhausted: true},
+		},
+		{
+			name: "409 is a conflict",
+			err:  apiErr(http.StatusConflict, "alreadyExists"),
+			want: classification{conflict: true},
+		},
+		{
+			name: "404 is not terminal",
+			err:  apiErr(http.StatusNotFound, "notFound"),
+			want: classification{notFound: true},
+		},
+		{
+			name: "404 of a resource of the spec is terminal",
+			err:  NewMissingResourceError("source image", apiErr(http.StatusNotFound, "notFound")),
+			want: classification{notFound: true, missingResource: true, terminal: true},
+		},
+		{
+			name: "wrapped with fmt",
+			err:  fmt.Errorf("failed to create instance: %w", apiErr(http.StatusBadRequest)),
+			want: classification{invalidArgument: true, terminal: true},
+		},
+		{
+			name: "wrapped with pkg/errors",
+			err:  pkgerrors.Wrap(apiErr(http.StatusNotFound), "failed to get instance"),
+			want: classification{notFound: true},
+		},
+		{
+			name: "wrapped missing resource",
+			err:  pkgerrors.Wrap(NewMissingResourceError("network", apiErr(http.StatusNotFound)), "failed to reconcile"),
+			want: classification{notFound: true, missingResource: true, terminal: true},
+		},
+		{
+			name: "name conflict is terminal",
+			err:  fmt.Errorf("failed to create image: %w", NewNameConflictError("image forge-build")),
+			want: classification{nameConflict: true, terminal: true},
+		},
+		{
+			name: "operation out of zone resources",
+			err:  apiErr(http.StatusServiceUnavailable, "ZONE_RESOURCE_POOL_EXHAUSTED"),
+			want: classification{resourceExhausted: true},
+		},
+		{
+			name: "operation out of zone resources with details",
+			err:  fmt.Errorf("operation failed: %w", apiErr(http.StatusInternalServerError, "ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS")),
+			want: classification{resourceExhausted: true},
+		},
+		{
+			name: "operation with a generic resource exhaustion is not a zone exhaustion",
+			err:  apiErr(http.StatusServiceUnavailable, "RESOURCE_EXHAUSTED"),
+			want: classification{},
+		},
+		{
+			name: "operation with an exceeded quota",
+			err:  apiErr(http.StatusForbidden, "QUOTA_EXCEEDED"),
+			want: classification{quotaExceeded: true},
+		},
+		{
+			name: "operation with an invalid field",
+			err:  apiErr(http.StatusBadRequest, "INVALID_FIELD_VALUE"),
+			want: classification{invalidArgument: true, terminal: true},
+		},
+		{
+			name: "operation with a reason matched by a later error item",
+			err:  apiErr(http.StatusBadRequest, "INVALID_FIELD_VALUE", "RESOURCE_NOT_READY"),
+			want: classification{conflict: true},
+		},
+		{
+			name: "reason in the message is not matched",
+			err:  &googleapi.Error{Code: http.StatusInternalServerError, Message: "ZONE_RESOURCE_POOL_EXHAUSTED - no resources"},
+			want: classification{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(classify(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIgnoreNotFound(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(IgnoreNotFound(apiErr(http.StatusNotFound))).To(Succeed())
+	g.Expect(IgnoreNotFound(fmt.Errorf("wrapped: %w", apiErr(http.StatusNotFound)))).To(Succeed())
+	g.Expect(IgnoreNotFound(apiErr(http.StatusBadRequest))).To(HaveOccurred())
+}
+
+func TestMissingResourceError(t *testing.T) {
+	g := NewWithT(t)
+
+	cause := apiErr(http.StatusNotFound)
+	err := NewMissingResourceError("source image debian-12", cause)
+	g.Expect(err.Error()).To(HavePrefix("source image debian-12 not found: "))
+	g.Expect(errors.Is(err, cause)).To(BeTrue())
+}
+
+func TestNameConflictError(t *testing.T) {
+	g := NewWithT(t)
+
+	err := NewNameConflictError("snapshot forge-build")
+	g.Expect(err.Error()).To(Equal("snapshot forge-build already exists and is not owned by the build"))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oslogin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/oslogin/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+)
+
+// Reconcile imports the SSH public key in the OS Login profile of the service account of the controller.
+func (s *Service) Reconcile(ctx context.Context) error {
+	if !s.scope.IsOSLogin() || s.scope.OSLoginProfile() != nil {
+		return nil
+	}
+
+	s.Log.Info("Reconciling OS Login profile")
+	serviceAccount, err := s.scope.ServiceAccountEmail(ctx)
+	if err != nil {
+		return err
+	}
+
+	users, err := s.usersService(ctx)
+	if err != nil {
+		return err
+	}
+
+	publicKey := s.scope.SSHPublicKey()
+	resp, err := users.ImportSshPublicKey(userName(serviceAccount), &oslogin.SshPublicKey{Key: publicKey}).
+		ProjectId(s.scope.Project()).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to import ssh key for %s: %w", serviceAccount, err)
+	}
+	if resp.LoginProfile == nil {
+		return fmt.Errorf("no login profile returned for %s", serviceAccount)
+	}
+
+	username := posixUsername(resp.LoginProfile)
+	if username == "" {
+		return fmt.Errorf("no posix account in the login profile of %s", serviceAccount)
+	}
+
+	fingerprint := keyFingerprint(resp.LoginProfile, publicKey)
+	if fingerprint == "" {
+		return fmt.Errorf("imported ssh key not found in the login profile of %s", serviceAccount)
+	}
+
+	s.Log.V(1).Info("Imported ssh key", "serviceAccount", serviceAccount, "username", username)
+	s.scope.SetOSLoginProfile(&infrav1.OSLoginStatus{
+		ServiceAccount: serviceAccount,
+		Username:       username,
+		Fingerprint:    fingerprint,
+	})
+
+	return nil
+}
+
+// Delete removes the SSH public key from the OS Login profile.
+func (s *Service) Delete(ctx context.Context) error {
+	profile := s.scope.OSLoginProfile()
+	if profile == nil {
+		return nil
+	}
+
+	sshPublicKeys, err := s.sshPublicKeysService(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.Log.Info("Deleting ssh key from OS Login profile", "serviceAccount", profile.ServiceAccount)
+	name := fmt.Sprintf("%s/sshPublicKeys/%s", userName(profile.ServiceAccount), profile.Fingerprint)
+	if _, err := sshPublicKeys.Delete(name).Context(ctx).Do(); err != nil && !gcperrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ssh key of %s: %w", profile.ServiceAccount, err)
+	}
+
+	s.scope.SetOSLoginProfile(nil)
+
+	return nil
+}
+
+func userName(serviceAccount string) string {
+	return fmt.Sprintf("users/%s", serviceAccount)
+}
+
+// posixUsername returns the username of the primary POSIX account of the profile, or of the first one.
+func posixUsername(profile *oslogin.LoginProfile) string {
+	for _, account := range profile.PosixAccounts {
+		if account.Primary {
+			return account.Username
+		}
+	}
+	if len(profile.PosixAccounts) > 0 {
+		return profile.PosixAccounts[0].Username
+	}
+
+	return ""
+}
+
+// keyFingerprint returns the fingerprint of the given key in the profile, keys are compared without their comment.
+func keyFingerprint(profile *oslogin.LoginProfile, publicKey string) string {
+	for fingerprint, key := range profile.SshPublicKeys {
+		if keyData(key.Key) == keyData(publicKey) {
+			if key.Fingerprint != "" {
+				return key.Fingerprint
+			}
+			return fingerprint
+		}
+	}
+
+	return ""
+}
+
+func keyData(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return key
+	}
+
+	return fields[0] + " " + fields[1]
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oslogin
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/api/oslogin/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
+)
+
+const ServiceName = "oslogin-reconciler"
+
+type usersInterface interface {
+	ImportSshPublicKey(parent string, sshpublickey *oslogin.SshPublicKey) *oslogin.UsersImportSshPublicKeyCall
+}
+
+type sshPublicKeysInterface interface {
+	Delete(name string) *oslogin.UsersSshPublicKeysDeleteCall
+}
+
+// Scope is an interfaces that hold used methods.
+type Scope interface {
+	cloud.Build
+	IsOSLogin() bool
+	SSHPublicKey() string
+	ServiceAccountEmail(ctx context.Context) (string, error)
+	OSLoginProfile() *infrav1.OSLoginStatus
+	SetOSLoginProfile(profile *infrav1.OSLoginStatus)
+	OSLoginService(ctx context.Context) (*oslogin.Service, error)
+}
+
+// Service implements OS Login reconciler.
+type Service struct {
+	scope         Scope
+	users         usersInterface
+	sshPublicKeys sshPublicKeysInterface
+	Log           logr.Logger
+}
+
+var _ cloud.Reconciler = &Service{}
+
+// New returns Service from given scope.
+func New(scope Scope) *Service {
+	return &Service{
+		scope: scope,
+		Log:   scope.Log(ServiceName),
+	}
+}
+
+// usersService returns the OS Login users service, created on first use as only OS Login builds need it.
+func (s *Service) usersService(ctx context.Context) (usersInterface, error) {
+	if s.users == nil {
+		osLoginSvc, err := s.scope.OSLoginService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.users = oslogin.NewUsersService(osLoginSvc)
+	}
+
+	return s.users, nil
+}
+
+// sshPublicKeysService returns the OS Login SSH public keys service, created on first use as only OS Login
+// builds need it.
+func (s *Service) sshPublicKeysService(ctx context.Context) (sshPublicKeysInterface, error) {
+	if s.sshPublicKeys == nil {
+		osLoginSvc, err := s.scope.OSLoginService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.sshPublicKeys = oslogin.NewUsersSshPublicKeysService(osLoginSvc)
+	}
+
+	return s.sshPublicKeys, nil
+}
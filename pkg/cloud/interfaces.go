@@ -22,6 +22,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
 	"github.com/go-logr/logr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // Cloud alias for cloud.Cloud interface.
@@ -61,6 +62,8 @@ type BuildSetter interface {
 	SetInstanceID(instanceID string)
 	SetInstanceStatus(v infrav1.InstanceStatus)
 	EnsureCredentialsSecret(ctx context.Context, host string) error
+	MarkConditionTrue(t clusterv1.ConditionType)
+	MarkConditionFalse(t clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string)
 }
 
 // Build is an interface which can get and set build information.
@@ -18,21 +18,32 @@ package scope
 
 import (
 	"context"
+	"crypto/rsa"
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/forge-build/forge/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/go-logr/logr"
 
 	buildv1 "github.com/forge-build/forge/pkg/api/v1alpha1"
 	"github.com/pkg/errors"
+	"google.golang.org/api/cloudbuild/v1"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/oslogin/v1"
+	"google.golang.org/api/storage/v1"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -40,6 +51,18 @@ import (
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
 )
 
+// buildPhaseConditions are the conditions of the build phases, in order, summarized in the Ready condition.
+var buildPhaseConditions = []clusterv1.ConditionType{
+	infrav1.NetworkReadyCondition,
+	infrav1.FirewallsReadyCondition,
+	infrav1.SubnetsReadyCondition,
+	infrav1.SourceImageResolvedCondition,
+	infrav1.InstanceProvisionedCondition,
+	infrav1.InstanceReadyCondition,
+	infrav1.MachineReadyCondition,
+	infrav1.ImageCreatedCondition,
+}
+
 // BuildScopeParams defines the input parameters used to create a new Scope.
 type BuildScopeParams struct {
 	GCPServices
@@ -47,6 +70,8 @@ type BuildScopeParams struct {
 	Build       *buildv1.Build
 	GCPBuild    *infrav1.GCPBuild
 	BuildGetter cloud.BuildGetter
+	Endpoints   ServiceEndpoints
+	Recorder    record.EventRecorder
 	Log         logr.Logger
 }
 
@@ -79,7 +104,9 @@ func NewBuildScope(ctx context.Context, params BuildScopeParams) (*BuildScope, e
 		Build:       params.Build,
 		GCPBuild:    params.GCPBuild,
 		GCPServices: params.GCPServices,
+		endpoints:   params.Endpoints,
 		Logger:      params.Log,
+		recorder:    params.Recorder,
 		patchHelper: helper,
 	}, nil
 }
@@ -87,6 +114,8 @@ func NewBuildScope(ctx context.Context, params BuildScopeParams) (*BuildScope, e
 // BuildScope defines the basic context for an actuator to operate upon.
 type BuildScope struct {
 	client      client.Client
+	recorder    record.EventRecorder
+	endpoints   ServiceEndpoints
 	patchHelper *patch.Helper
 
 	Build    *buildv1.Build
@@ -94,10 +123,21 @@ type BuildScope struct {
 	Logger   logr.Logger
 	GCPServices
 
-	sshKEy SSHKey
+	sshKEy                 SSHKey
+	suppliedKeys           map[string]string
+	sourceBuildArtifactRef string
+	windowsKey             *rsa.PrivateKey
+	windowsPassword        string
 }
 
-const sshMetaKey = "ssh-keys"
+const (
+	sshMetaKey     = "ssh-keys"
+	osLoginMetaKey = "enable-oslogin"
+
+	guestAttributesMetaKey = "enable-guest-attributes"
+
+	defaultMaxPreemptionRetries int32 = 3
+)
 
 type SSHKey struct {
 	MetadataSSHKeys string
@@ -200,6 +240,36 @@ func (s *BuildScope) SetSSHKey(key SSHKey) {
 	s.sshKEy = key
 }
 
+// Preemptions returns how many times the builder instance has been recreated after a preemption.
+func (s *BuildScope) Preemptions() int32 {
+	return s.GCPBuild.Status.Preemptions
+}
+
+// MaxPreemptionRetries returns how many times the builder instance may be recreated after a preemption.
+func (s *BuildScope) MaxPreemptionRetries() int32 {
+	return ptr.Deref(s.GCPBuild.Spec.MaxPreemptionRetries, defaultMaxPreemptionRetries)
+}
+
+// RecordPreemption counts a preemption and resets the instance so that it gets recreated.
+func (s *BuildScope) RecordPreemption() {
+	s.GCPBuild.Status.Preemptions++
+	s.GCPBuild.Spec.InstanceID = nil
+	s.GCPBuild.Status.InstanceStatus = nil
+	s.GCPBuild.Status.MachineReady = false
+}
+
+// SetFailure sets the terminal failure reason and message of the build, and reports them in the Ready condition.
+func (s *BuildScope) SetFailure(reason, message string) {
+	s.GCPBuild.Status.FailureReason = &reason
+	s.GCPBuild.Status.FailureMessage = &message
+	conditions.MarkFalse(s.GCPBuild, clusterv1.ReadyCondition, reason, clusterv1.ConditionSeverityError, "%s", message)
+}
+
+// HasFailed returns true if the build hit a terminal failure.
+func (s *BuildScope) HasFailed() bool {
+	return s.GCPBuild.Status.FailureReason != nil
+}
+
 // SetReady sets cluster ready status.
 func (s *BuildScope) SetReady() {
 	s.GCPBuild.Status.Ready = true
@@ -208,17 +278,51 @@ func (s *BuildScope) SetReady() {
 // SetMachineReady sets build machine ready status.
 func (s *BuildScope) SetMachineReady() {
 	s.GCPBuild.Status.MachineReady = true
+	conditions.MarkTrue(s.GCPBuild, infrav1.MachineReadyCondition)
 }
 
 // SetBuildReady sets cleanup ready status.
 func (s *BuildScope) SetCleanedUP() {
 	s.GCPBuild.Status.CleanedUP = true
+	conditions.MarkTrue(s.GCPBuild, infrav1.CleanedUpCondition)
+}
+
+// MarkConditionTrue sets the condition to true.
+func (s *BuildScope) MarkConditionTrue(t clusterv1.ConditionType) {
+	conditions.MarkTrue(s.GCPBuild, t)
+}
+
+// MarkConditionFalse sets the condition to false with the reason and message explaining why.
+func (s *BuildScope) MarkConditionFalse(t clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string) {
+	conditions.MarkFalse(s.GCPBuild, t, reason, severity, "%s", message)
 }
 
 func (s *BuildScope) SetArtifactRef(reference string) {
 	s.GCPBuild.Status.ArtifactRef = &reference
 }
 
+// SetImageStatus reflects the given produced image in the status.
+func (s *BuildScope) SetImageStatus(image *compute.Image) {
+	status := &infrav1.ImageStatus{
+		Name:             image.Name,
+		SelfLink:         ptr.To(image.SelfLink),
+		Labels:           image.Labels,
+		StorageLocations: image.StorageLocations,
+		Licenses:         image.Licenses,
+	}
+	if image.Family != "" {
+		status.Family = ptr.To(image.Family)
+	}
+	if image.Architecture != "" {
+		status.Architecture = ptr.To(image.Architecture)
+	}
+	for _, feature := range image.GuestOsFeatures {
+		status.GuestOSFeatures = append(status.GuestOSFeatures, feature.Type)
+	}
+
+	s.GCPBuild.Status.Image = status
+}
+
 // NetworkSpec returns google compute network spec.
 func (s *BuildScope) NetworkSpec() *compute.Network {
 	createSubnet := ptr.Deref(s.GCPBuild.Spec.Network.AutoCreateSubnetworks, true)
@@ -315,21 +419,144 @@ func (s *BuildScope) FirewallRulesSpec() []*compute.Firewall {
 		},
 	}
 
+	if s.ConnectionStrategy() == infrav1.IAPTunnelConnectionStrategy {
+		firewallRules = append(firewallRules, s.IAPFirewallRuleSpec())
+	}
+
 	return firewallRules
 }
 
-// Zone returns the FailureDomain for the GCPBuild.
+// Zone returns the zone the builder instance is created in.
 func (s *BuildScope) Zone() string {
+	if s.GCPBuild.Status.Zone != "" {
+		return s.GCPBuild.Status.Zone
+	}
+
 	return s.GCPBuild.Spec.Zone
 }
 
+// FailureDomains returns the zones the builder instance falls back to, empty for all the zones of the region.
+func (s *BuildScope) FailureDomains() []string {
+	return s.GCPBuild.Spec.FailureDomains
+}
+
+// ExhaustedZones returns the zones that had not enough resources to create the builder instance.
+func (s *BuildScope) ExhaustedZones() []string {
+	return s.GCPBuild.Status.ExhaustedZones
+}
+
+// MoveToZone records the current zone as exhausted and creates the builder instance in the given zone.
+func (s *BuildScope) MoveToZone(zone string) {
+	s.GCPBuild.Status.ExhaustedZones = append(s.GCPBuild.Status.ExhaustedZones, s.Zone())
+	s.GCPBuild.Status.Zone = zone
+}
+
+// ClearExhaustedZones forgets the exhausted zones once the builder instance exists, the instance stays in its zone.
+func (s *BuildScope) ClearExhaustedZones() {
+	s.GCPBuild.Status.ExhaustedZones = nil
+}
+
+// ResetZone creates the builder instance in spec.zone again and forgets the exhausted zones.
+func (s *BuildScope) ResetZone() {
+	s.GCPBuild.Status.Zone = ""
+	s.GCPBuild.Status.ExhaustedZones = nil
+}
+
+// SourceImageReference returns the reference of the image the instance is created from,
+// and whether it refers to an image family.
+func (s *BuildScope) SourceImageReference() (string, bool) {
+	if s.GCPBuild.Spec.Image != nil {
+		return *s.GCPBuild.Spec.Image, false
+	}
+	if s.GCPBuild.Spec.ImageFamily != nil {
+		return *s.GCPBuild.Spec.ImageFamily, true
+	}
+
+	return s.sourceBuildArtifactRef, false
+}
+
+// SourceBuildRef returns the reference to the GCPBuild the image the instance is created from comes from.
+// It is nil when an image or an image family is specified, as they take precedence.
+func (s *BuildScope) SourceBuildRef() *corev1.LocalObjectReference {
+	if s.GCPBuild.Spec.Image != nil || s.GCPBuild.Spec.ImageFamily != nil {
+		return nil
+	}
+
+	return s.GCPBuild.Spec.SourceBuildRef
+}
+
+// ResolveSourceBuild looks up the GCPBuild the image the instance is created from comes from,
+// and returns true once it is ready and its image can be used. The build fails if the source build has failed.
+func (s *BuildScope) ResolveSourceBuild(ctx context.Context) (bool, error) {
+	ref := s.SourceBuildRef()
+	if ref == nil {
+		return true, nil
+	}
+	if ref.Name == s.GCPBuild.Name {
+		return false, errors.New("a GCPBuild can't use itself as source build")
+	}
+
+	sourceBuild := &infrav1.GCPBuild{}
+	key := types.NamespacedName{Namespace: s.GCPBuild.Namespace, Name: ref.Name}
+	if err := s.client.Get(ctx, key, sourceBuild); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, errors.Wrapf(err, "failed to get source build %s", key)
+		}
+
+		conditions.MarkFalse(s.GCPBuild, infrav1.SourceImageResolvedCondition, infrav1.WaitingForSourceBuildReason, clusterv1.ConditionSeverityInfo, "Source build %s does not exist yet", ref.Name)
+		return false, nil
+	}
+
+	// The source build won't produce an image anymore, fail the build instead of waiting forever.
+	if sourceBuild.Status.FailureReason != nil {
+		message := fmt.Sprintf("Source build %s has failed: %s", ref.Name, *sourceBuild.Status.FailureReason)
+		conditions.MarkFalse(s.GCPBuild, infrav1.SourceImageResolvedCondition, infrav1.SourceBuildFailedReason, clusterv1.ConditionSeverityError, message)
+		s.SetFailure(infrav1.SourceBuildFailedReason, message)
+		return false, nil
+	}
+
+	if !sourceBuild.Status.Ready || sourceBuild.Status.ArtifactRef == nil {
+		conditions.MarkFalse(s.GCPBuild, infrav1.SourceImageResolvedCondition, infrav1.WaitingForSourceBuildReason, clusterv1.ConditionSeverityInfo, "Waiting for source build %s to be ready", ref.Name)
+		return false, nil
+	}
+
+	if sourceBuild.Status.Artifact != nil && sourceBuild.Status.Artifact.Type != infrav1.ImageArtifactType {
+		return false, errors.Errorf("source build %s produces a %s, only images can be used as source", ref.Name, sourceBuild.Status.Artifact.Type)
+	}
+
+	s.sourceBuildArtifactRef = *sourceBuild.Status.ArtifactRef
+	return true, nil
+}
+
+// SourceImageFilter returns the filter selecting the image the instance is created from.
+func (s *BuildScope) SourceImageFilter() *infrav1.SourceImageFilter {
+	return s.GCPBuild.Spec.SourceImageFilter
+}
+
+// SourceImage returns the resolved image the instance is created from.
+func (s *BuildScope) SourceImage() *infrav1.SourceImageStatus {
+	return s.GCPBuild.Status.SourceImage
+}
+
+// SetSourceImage pins the image the instance is created from for the rest of the build.
+func (s *BuildScope) SetSourceImage(image *compute.Image) {
+	s.GCPBuild.Status.SourceImage = &infrav1.SourceImageStatus{
+		SelfLink: image.SelfLink,
+		ID:       strconv.FormatUint(image.Id, 10),
+	}
+	conditions.MarkTrue(s.GCPBuild, infrav1.SourceImageResolvedCondition)
+}
+
+// MarkSourceImageUnresolved reports that the image the instance is created from can't be resolved.
+func (s *BuildScope) MarkSourceImageUnresolved(reason, message string) {
+	conditions.MarkFalse(s.GCPBuild, infrav1.SourceImageResolvedCondition, reason, clusterv1.ConditionSeverityError, "%s", message)
+}
+
 // InstanceImageSpec returns compute instance image attched-disk spec.
 func (s *BuildScope) InstanceImageSpec() *compute.AttachedDisk {
-	sourceImage := path.Join("global", "images", "ubuntu-2204-jammy-v20240904")
-	if s.GCPBuild.Spec.Image != nil {
-		sourceImage = *s.GCPBuild.Spec.Image
-	} else if s.GCPBuild.Spec.ImageFamily != nil {
-		sourceImage = *s.GCPBuild.Spec.ImageFamily
+	var sourceImage string
+	if pinned := s.SourceImage(); pinned != nil {
+		sourceImage = pinned.SelfLink
 	}
 
 	diskType := infrav1.PdStandardDiskType
@@ -346,18 +573,56 @@ func (s *BuildScope) InstanceImageSpec() *compute.AttachedDisk {
 			SourceImage: sourceImage,
 			Labels:      s.AdditionalLabels().AddLabels(s.GCPBuild.Spec.AdditionalLabels),
 		},
+		DiskEncryptionKey: s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.RootDeviceEncryptionKey),
 	}
 
 	return disk
 }
 
+// InstanceAdditionalDiskSpec returns compute instance additional attched-disk spec.
+func (s *BuildScope) InstanceAdditionalDiskSpec() []*compute.AttachedDisk {
+	additionalDisks := make([]*compute.AttachedDisk, 0, len(s.GCPBuild.Spec.AdditionalDisks))
+	for _, disk := range s.GCPBuild.Spec.AdditionalDisks {
+		diskType := ptr.Deref(disk.DeviceType, infrav1.PdStandardDiskType)
+		additionalDisk := &compute.AttachedDisk{
+			AutoDelete: ptr.Deref(disk.AutoDelete, true),
+			DeviceName: ptr.Deref(disk.DeviceName, ""),
+			InitializeParams: &compute.AttachedDiskInitializeParams{
+				DiskSizeGb: ptr.Deref(disk.Size, 30),
+				DiskType:   path.Join("zones", s.Zone(), "diskTypes", string(diskType)),
+				Labels:     s.AdditionalLabels().AddLabels(s.GCPBuild.Spec.AdditionalLabels),
+			},
+		}
+		if diskType == infrav1.LocalSsdDiskType {
+			// Local SSDs are scratch disks, they have a fixed size and can't outlive the instance.
+			additionalDisk.Type = "SCRATCH"
+			additionalDisk.AutoDelete = true
+			additionalDisk.InitializeParams.DiskSizeGb = 375
+			additionalDisk.InitializeParams.Labels = nil
+			// NVME is faster than the default SCSI interface for local SSDs.
+			additionalDisk.Interface = string(infrav1.NVMEDiskInterface)
+		}
+		if disk.Interface != nil {
+			additionalDisk.Interface = string(*disk.Interface)
+		}
+		// Local SSDs are always encrypted with Google-managed keys, GCP rejects encryption keys for them.
+		if additionalDisk.Type != "SCRATCH" {
+			additionalDisk.DiskEncryptionKey = s.CustomerEncryptionKeySpec(disk.EncryptionKey)
+		}
+
+		additionalDisks = append(additionalDisks, additionalDisk)
+	}
+
+	return additionalDisks
+}
+
 // InstanceNetworkInterfaceSpec returns compute network interface spec.
 func (s *BuildScope) InstanceNetworkInterfaceSpec() *compute.NetworkInterface {
 	networkInterface := &compute.NetworkInterface{
 		Network: path.Join("projects", s.NetworkProject(), "global", "networks", s.NetworkName()),
 	}
 
-	if s.GCPBuild.Spec.PublicIP != nil && *s.GCPBuild.Spec.PublicIP {
+	if ptr.Deref(s.GCPBuild.Spec.PublicIP, false) || s.ConnectionStrategy() == infrav1.PublicIPConnectionStrategy {
 		networkInterface.AccessConfigs = []*compute.AccessConfig{
 			{
 				Type: "ONE_TO_ONE_NAT",
@@ -400,15 +665,145 @@ func (s *BuildScope) InstanceAdditionalMetadataSpec() *compute.Metadata {
 		})
 	}
 
-	// Add the ssh keys.
-	metadata.Items = append(metadata.Items, &compute.MetadataItems{
-		Key:   sshMetaKey,
-		Value: &s.sshKEy.MetadataSSHKeys,
-	})
+	// Add the ssh keys, OS Login ignores them.
+	if s.IsOSLogin() {
+		metadata.Items = append(metadata.Items, &compute.MetadataItems{
+			Key:   osLoginMetaKey,
+			Value: ptr.To("TRUE"),
+		})
+	} else {
+		metadata.Items = append(metadata.Items, &compute.MetadataItems{
+			Key:   sshMetaKey,
+			Value: &s.sshKEy.MetadataSSHKeys,
+		})
+	}
+
+	// Ask the guest agent to create the user and set its password.
+	if s.IsWindows() {
+		windowsKeys, err := s.windowsKeysMetadata()
+		if err != nil {
+			s.Logger.Error(err, "Unable to set the windows-keys metadata")
+		} else {
+			metadata.Items = append(metadata.Items, &compute.MetadataItems{
+				Key:   windowsKeysMetaKey,
+				Value: &windowsKeys,
+			})
+		}
+	}
+
+	// Let the guest write the attribute the readiness gate waits on.
+	if s.ReadinessGuestAttribute() != "" {
+		metadata.Items = append(metadata.Items, &compute.MetadataItems{
+			Key:   guestAttributesMetaKey,
+			Value: ptr.To("TRUE"),
+		})
+	}
 
 	return metadata
 }
 
+// InstanceSchedulingSpec returns compute instance scheduling spec.
+func (s *BuildScope) InstanceSchedulingSpec() *compute.Scheduling {
+	scheduling := &compute.Scheduling{
+		Preemptible: s.GCPBuild.Spec.Preemptible,
+	}
+
+	if ptr.Deref(s.GCPBuild.Spec.ProvisioningModel, infrav1.ProvisioningModelStandard) == infrav1.ProvisioningModelSpot {
+		action := ptr.Deref(s.GCPBuild.Spec.InstanceTerminationAction, infrav1.InstanceTerminationActionDelete)
+		scheduling.ProvisioningModel = "SPOT"
+		scheduling.InstanceTerminationAction = strings.ToUpper(string(action))
+		scheduling.AutomaticRestart = ptr.To(false)
+		scheduling.OnHostMaintenance = "TERMINATE"
+	}
+
+	if s.IsConfidentialCompute() {
+		scheduling.OnHostMaintenance = "TERMINATE"
+	}
+
+	// Let GCP delete the instance at the deadline of the build, in case the controller can't.
+	if deadline, ok := s.Deadline(); ok {
+		runDuration := time.Until(deadline)
+		if runDuration < minMaxRunDuration {
+			runDuration = minMaxRunDuration
+		}
+		scheduling.MaxRunDuration = &compute.Duration{Seconds: int64(runDuration.Seconds())}
+		scheduling.InstanceTerminationAction = "DELETE"
+	}
+
+	return scheduling
+}
+
+// IsConfidentialCompute returns true if the builder instance is a Confidential VM.
+func (s *BuildScope) IsConfidentialCompute() bool {
+	security := s.GCPBuild.Spec.Security
+	if security == nil || security.ConfidentialCompute == nil {
+		return false
+	}
+
+	return *security.ConfidentialCompute != infrav1.ConfidentialComputePolicyDisabled
+}
+
+// InstanceShieldedInstanceConfigSpec returns compute instance shielded VM spec.
+func (s *BuildScope) InstanceShieldedInstanceConfigSpec() *compute.ShieldedInstanceConfig {
+	security := s.GCPBuild.Spec.Security
+	if security == nil || security.ShieldedInstanceConfig == nil {
+		return nil
+	}
+
+	config := &compute.ShieldedInstanceConfig{
+		EnableSecureBoot:          security.ShieldedInstanceConfig.SecureBoot == infrav1.SecureBootPolicyEnabled,
+		EnableVtpm:                security.ShieldedInstanceConfig.VirtualizedTrustedPlatformModule != infrav1.VirtualizedTrustedPlatformModulePolicyDisabled,
+		EnableIntegrityMonitoring: security.ShieldedInstanceConfig.IntegrityMonitoring != infrav1.IntegrityMonitoringPolicyDisabled,
+		ForceSendFields:           []string{"EnableSecureBoot", "EnableVtpm", "EnableIntegrityMonitoring"},
+	}
+
+	return config
+}
+
+// InstanceConfidentialInstanceConfigSpec returns compute instance confidential VM spec.
+func (s *BuildScope) InstanceConfidentialInstanceConfigSpec() *compute.ConfidentialInstanceConfig {
+	if !s.IsConfidentialCompute() {
+		return nil
+	}
+
+	config := &compute.ConfidentialInstanceConfig{
+		EnableConfidentialCompute: true,
+	}
+
+	switch *s.GCPBuild.Spec.Security.ConfidentialCompute {
+	case infrav1.ConfidentialComputePolicySEVSNP:
+		config.ConfidentialInstanceType = "SEV_SNP"
+	case infrav1.ConfidentialComputePolicyTDX:
+		config.ConfidentialInstanceType = "TDX"
+	default:
+		config.ConfidentialInstanceType = "SEV"
+	}
+
+	return config
+}
+
+// ImageGuestOSFeatures returns the guest OS features the produced image needs
+// to boot with the security settings of the builder instance.
+func (s *BuildScope) ImageGuestOSFeatures() []string {
+	features := []string{}
+	if s.InstanceShieldedInstanceConfigSpec() != nil || s.IsConfidentialCompute() {
+		features = append(features, "UEFI_COMPATIBLE")
+	}
+
+	if confidentialConfig := s.InstanceConfidentialInstanceConfigSpec(); confidentialConfig != nil {
+		switch confidentialConfig.ConfidentialInstanceType {
+		case "SEV_SNP":
+			features = append(features, "SEV_CAPABLE", "SEV_SNP_CAPABLE")
+		case "TDX":
+			features = append(features, "TDX_CAPABLE")
+		default:
+			features = append(features, "SEV_CAPABLE")
+		}
+	}
+
+	return features
+}
+
 // InstanceSpec returns instance spec.
 func (s *BuildScope) InstanceSpec(log logr.Logger) *compute.Instance {
 	instance := &compute.Instance{
@@ -429,12 +824,13 @@ func (s *BuildScope) InstanceSpec(log logr.Logger) *compute.Instance {
 			// TODO: Check what needs to be added for the cloud provider label.
 			Additional: s.AdditionalLabels().AddLabels(s.GCPBuild.Spec.AdditionalLabels),
 		}),
-		Scheduling: &compute.Scheduling{
-			Preemptible: s.GCPBuild.Spec.Preemptible,
-		},
+		Scheduling:                 s.InstanceSchedulingSpec(),
+		ShieldedInstanceConfig:     s.InstanceShieldedInstanceConfigSpec(),
+		ConfidentialInstanceConfig: s.InstanceConfidentialInstanceConfigSpec(),
 	}
 
 	instance.Disks = append(instance.Disks, s.InstanceImageSpec())
+	instance.Disks = append(instance.Disks, s.InstanceAdditionalDiskSpec()...)
 	instance.Metadata = s.InstanceAdditionalMetadataSpec()
 	instance.ServiceAccounts = append(instance.ServiceAccounts, s.InstanceServiceAccountsSpec())
 	instance.NetworkInterfaces = append(instance.NetworkInterfaces, s.InstanceNetworkInterfaceSpec())
@@ -466,19 +862,48 @@ func (s *BuildScope) GetBootstrapData() (string, error) {
 func (s *BuildScope) EnsureCredentialsSecret(ctx context.Context, host string) error {
 	err := util.EnsureCredentialsSecret(ctx, s.client, s.Build, util.SSHCredentials{
 		Host:       host,
-		Username:   s.GCPBuild.Spec.Username,
+		Username:   s.ConnectionUsername(),
+		Password:   s.windowsPassword,
 		PrivateKey: s.sshKEy.PrivateKey,
 		PublicKey:  s.sshKEy.PublicKey,
 	}, "gcp")
 	if err != nil {
 		return err
 	}
+
+	if s.IsWindows() {
+		if err := s.ensureWinRMCredentials(ctx); err != nil {
+			return err
+		}
+	}
+	if s.ConnectionStrategy() == infrav1.IAPTunnelConnectionStrategy {
+		return s.ensureIAPTunnelCredentials(ctx)
+	}
 	return nil
 }
 
 // PatchObject persists the cluster configuration and status.
 func (s *BuildScope) PatchObject() error {
-	return s.patchHelper.Patch(context.TODO(), s.GCPBuild)
+	// Summarize the conditions of the build phases in the Ready condition, its reason is the one of the phase
+	// the build is stuck at. The Ready condition of a failed build keeps reporting the failure set by SetFailure.
+	if !s.HasFailed() {
+		conditions.SetSummary(s.GCPBuild,
+			conditions.WithConditions(buildPhaseConditions...),
+			conditions.WithStepCounterIf(s.GCPBuild.DeletionTimestamp.IsZero() && !s.GCPBuild.Status.Ready),
+		)
+	}
+
+	return s.patchHelper.Patch(context.TODO(), s.GCPBuild, patch.WithOwnedConditions{
+		Conditions: append([]clusterv1.ConditionType{clusterv1.ReadyCondition, infrav1.CleanedUpCondition, infrav1.ImageRetentionAppliedCondition}, buildPhaseConditions...),
+	})
+}
+
+// RecordEvent records an event on the GCPBuild.
+func (s *BuildScope) RecordEvent(eventType, reason, message string) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Event(s.GCPBuild, eventType, reason, message)
 }
 
 // Close closes the current scope persisting the cluster configuration and status.
@@ -486,8 +911,38 @@ func (s *BuildScope) Close() error {
 	return s.PatchObject()
 }
 
-func (s *BuildScope) ImageName() string {
-	return fmt.Sprintf("%s-%s", "forge", s.Name())
+// ImageSpec returns compute image spec for the image captured from the instance boot disk.
+func (s *BuildScope) ImageSpec() *compute.Image {
+	output := s.outputSpec()
+
+	image := &compute.Image{
+		Name:             s.ArtifactName(),
+		Family:           ptr.Deref(output.Family, ""),
+		SourceDisk:       s.sourceDisk(),
+		Description:      ptr.Deref(output.Description, fmt.Sprintf("Custom disk image created from instance: %s", s.Name())),
+		StorageLocations: output.StorageLocations,
+		Licenses:         output.Licenses,
+		Architecture:     ptr.Deref(output.Architecture, ""),
+
+		ImageEncryptionKey: s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.ImageEncryptionKey),
+		Labels:             s.outputLabels(),
+	}
+
+	// Reading a boot disk encrypted with a customer-supplied key requires the key.
+	if isCustomerSuppliedKey(s.GCPBuild.Spec.RootDeviceEncryptionKey) {
+		image.SourceDiskEncryptionKey = s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.RootDeviceEncryptionKey)
+	}
+
+	features := sets.New[string]()
+	for _, feature := range append(s.ImageGuestOSFeatures(), output.GuestOSFeatures...) {
+		if features.Has(feature) {
+			continue
+		}
+		features.Insert(feature)
+		image.GuestOsFeatures = append(image.GuestOsFeatures, &compute.GuestOsFeature{Type: feature})
+	}
+
+	return image
 }
 
 func (s *BuildScope) IsProvisionerReady() bool {
@@ -502,7 +957,64 @@ func (s *BuildScope) IsCleanedUP() bool {
 	return s.GCPBuild.Status.CleanedUP
 }
 
+// IsDeleting returns true if the GCPBuild is being deleted.
+func (s *BuildScope) IsDeleting() bool {
+	return !s.GCPBuild.DeletionTimestamp.IsZero()
+}
+
+// ArtifactRef returns the reference of the produced image.
+func (s *BuildScope) ArtifactRef() *string {
+	return s.GCPBuild.Status.ArtifactRef
+}
+
+// ArtifactDeletionPolicy returns what happens to the produced image when the GCPBuild is deleted.
+func (s *BuildScope) ArtifactDeletionPolicy() infrav1.ArtifactDeletionPolicy {
+	return ptr.Deref(s.GCPBuild.Spec.ArtifactDeletionPolicy, infrav1.ArtifactDeletionPolicyRetain)
+}
+
 // Implement the method to return the Compute service
 func (b *BuildScope) GetComputeService() *compute.Service {
 	return b.GCPServices.Compute
 }
+
+// CloudBuildService returns the Cloud Build service. Only image exports need it, it is created on first use.
+func (b *BuildScope) CloudBuildService(ctx context.Context) (*cloudbuild.Service, error) {
+	if b.GCPServices.CloudBuild == nil {
+		cloudBuildSvc, err := newCloudBuildService(ctx, b.GCPBuild.Spec.CredentialsRef, b.client)
+		if err != nil {
+			return nil, errors.Errorf("failed to create gcp cloud build client: %v", err)
+		}
+
+		b.GCPServices.CloudBuild = cloudBuildSvc
+	}
+
+	return b.GCPServices.CloudBuild, nil
+}
+
+// StorageService returns the Cloud Storage service. Only image exports need it, it is created on first use.
+func (b *BuildScope) StorageService(ctx context.Context) (*storage.Service, error) {
+	if b.GCPServices.Storage == nil {
+		storageSvc, err := newStorageService(ctx, b.GCPBuild.Spec.CredentialsRef, b.client, b.endpoints.Storage)
+		if err != nil {
+			return nil, errors.Errorf("failed to create gcp storage client: %v", err)
+		}
+
+		b.GCPServices.Storage = storageSvc
+	}
+
+	return b.GCPServices.Storage, nil
+}
+
+// OSLoginService returns the OS Login service. Only OS Login builds need it, it is created on first use.
+func (b *BuildScope) OSLoginService(ctx context.Context) (*oslogin.Service, error) {
+	if b.GCPServices.OSLogin == nil {
+		osLoginSvc, err := newOSLoginService(ctx, b.GCPBuild.Spec.CredentialsRef, b.client, b.endpoints.OSLogin)
+		if err != nil {
+			return nil, errors.Errorf("failed to create gcp os login client: %v", err)
+		}
+
+		b.GCPServices.OSLogin = osLoginSvc
+	}
+
+	return b.GCPServices.OSLogin, nil
+}
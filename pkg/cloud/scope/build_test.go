@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+func TestResolveSourceBuild(t *testing.T) {
+	tests := []struct {
+		name        string
+		sourceBuild *infrav1.GCPBuild
+		wantReady   bool
+		wantReason  string
+		wantFailed  bool
+	}{
+		{
+			name:       "source build does not exist",
+			wantReason: infrav1.WaitingForSourceBuildReason,
+		},
+		{
+			name: "source build not ready",
+			sourceBuild: &infrav1.GCPBuild{
+				ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default"},
+			},
+			wantReason: infrav1.WaitingForSourceBuildReason,
+		},
+		{
+			name: "source build failed",
+			sourceBuild: &infrav1.GCPBuild{
+				ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default"},
+				Status: infrav1.GCPBuildStatus{
+					FailureReason:  ptr.To(infrav1.BuildTimeoutReason),
+					FailureMessage: ptr.To("build did not complete within 1h"),
+				},
+			},
+			wantReason: infrav1.SourceBuildFailedReason,
+			wantFailed: true,
+		},
+		{
+			name: "source build ready",
+			sourceBuild: &infrav1.GCPBuild{
+				ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default"},
+				Status: infrav1.GCPBuildStatus{
+					Ready:       true,
+					ArtifactRef: ptr.To("projects/my-project/global/images/base"),
+				},
+			},
+			wantReady: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.sourceBuild != nil {
+				builder = builder.WithObjects(tt.sourceBuild)
+			}
+			s := &BuildScope{
+				client: builder.Build(),
+				GCPBuild: &infrav1.GCPBuild{
+					ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+					Spec: infrav1.GCPBuildSpec{
+						SourceBuildRef: &corev1.LocalObjectReference{Name: "base"},
+					},
+				},
+			}
+
+			ready, err := s.ResolveSourceBuild(context.Background())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ready).To(Equal(tt.wantReady))
+			g.Expect(s.HasFailed()).To(Equal(tt.wantFailed))
+			if tt.wantFailed {
+				g.Expect(s.GCPBuild.Status.FailureReason).To(Equal(ptr.To(infrav1.SourceBuildFailedReason)))
+			}
+			if tt.wantReason != "" {
+				g.Expect(conditions.GetReason(s.GCPBuild, infrav1.SourceImageResolvedCondition)).To(Equal(tt.wantReason))
+			}
+		})
+	}
+}
+
+func TestSetFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	gcpBuild := &infrav1.GCPBuild{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gcpBuild).WithStatusSubresource(gcpBuild).Build()
+	helper, err := patch.NewHelper(gcpBuild, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &BuildScope{client: c, GCPBuild: gcpBuild, patchHelper: helper}
+
+	conditions.MarkTrue(s.GCPBuild, infrav1.NetworkReadyCondition)
+	conditions.MarkFalse(s.GCPBuild, infrav1.InstanceProvisionedCondition, infrav1.InstanceCreatingReason, clusterv1.ConditionSeverityInfo, "")
+	s.SetFailure(infrav1.PermissionDeniedReason, "compute.instances.create permission denied")
+
+	// The summary of the build phases doesn't replace the failure.
+	g.Expect(s.PatchObject()).To(Succeed())
+
+	patched := &infrav1.GCPBuild{}
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(gcpBuild), patched)).To(Succeed())
+	for _, build := range []*infrav1.GCPBuild{s.GCPBuild, patched} {
+		ready := conditions.Get(build, clusterv1.ReadyCondition)
+		g.Expect(ready).NotTo(BeNil())
+		g.Expect(ready.Status).To(Equal(corev1.ConditionFalse))
+		g.Expect(ready.Reason).To(Equal(infrav1.PermissionDeniedReason))
+		g.Expect(ready.Severity).To(Equal(clusterv1.ConditionSeverityError))
+		g.Expect(ready.Message).To(Equal("compute.instances.create permission denied"))
+	}
+}
+
+func TestInstanceAdditionalDiskSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	encryptionKey := &infrav1.CustomerEncryptionKey{
+		KeyType:    infrav1.CustomerManagedKey,
+		ManagedKey: &infrav1.ManagedKey{KMSKeyName: "projects/my-project/locations/global/keyRings/ring/cryptoKeys/key"},
+	}
+	s := newImageScope(infrav1.GCPBuildSpec{
+		Zone: "europe-west1-b",
+		AdditionalDisks: []infrav1.AttachedDiskSpec{
+			{DeviceType: ptr.To(infrav1.PdSsdDiskType), EncryptionKey: encryptionKey},
+			{DeviceType: ptr.To(infrav1.LocalSsdDiskType), EncryptionKey: encryptionKey},
+		},
+	}, infrav1.GCPBuildStatus{})
+
+	disks := s.InstanceAdditionalDiskSpec()
+	g.Expect(disks).To(HaveLen(2))
+	g.Expect(disks[0].DiskEncryptionKey).NotTo(BeNil())
+	g.Expect(disks[0].DiskEncryptionKey.KmsKeyName).To(Equal(encryptionKey.ManagedKey.KMSKeyName))
+	g.Expect(disks[1].Type).To(Equal("SCRATCH"))
+	g.Expect(disks[1].DiskEncryptionKey).To(BeNil())
+}
@@ -24,8 +24,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"google.golang.org/api/cloudbuild/v1"
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/option"
+	"google.golang.org/api/oslogin/v1"
+	"google.golang.org/api/storage/v1"
 	"k8s.io/client-go/pkg/version"
 	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,7 +36,18 @@ import (
 
 // GCPServices contains all the gcp services used by the scopes.
 type GCPServices struct {
-	Compute *compute.Service
+	Compute    *compute.Service
+	CloudBuild *cloudbuild.Service
+	Storage    *storage.Service
+	OSLogin    *oslogin.Service
+}
+
+// ServiceEndpoints overrides the default endpoints of the gcp services, e.g. to use emulators.
+type ServiceEndpoints struct {
+	// Storage is the endpoint of the Cloud Storage API.
+	Storage string
+	// OSLogin is the endpoint of the OS Login API.
+	OSLogin string
 }
 
 // GCPRateLimiter implements cloud.RateLimiter.
@@ -98,3 +112,51 @@ func newComputeService(ctx context.Context, credentialsRef *corev1.SecretReferen
 
 	return computeSvc, nil
 }
+
+func newCloudBuildService(ctx context.Context, credentialsRef *corev1.SecretReference, crClient client.Client) (*cloudbuild.Service, error) {
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+	if err != nil {
+		return nil, fmt.Errorf("getting default gcp client options: %w", err)
+	}
+
+	cloudBuildSvc, err := cloudbuild.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new cloud build service instance: %w", err)
+	}
+
+	return cloudBuildSvc, nil
+}
+
+func newStorageService(ctx context.Context, credentialsRef *corev1.SecretReference, crClient client.Client, endpoint string) (*storage.Service, error) {
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+	if err != nil {
+		return nil, fmt.Errorf("getting default gcp client options: %w", err)
+	}
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	storageSvc, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new storage service instance: %w", err)
+	}
+
+	return storageSvc, nil
+}
+
+func newOSLoginService(ctx context.Context, credentialsRef *corev1.SecretReference, crClient client.Client, endpoint string) (*oslogin.Service, error) {
+	opts, err := defaultClientOptions(ctx, credentialsRef, crClient)
+	if err != nil {
+		return nil, fmt.Errorf("getting default gcp client options: %w", err)
+	}
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	osLoginSvc, err := oslogin.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating new os login service instance: %w", err)
+	}
+
+	return osLoginSvc, nil
+}
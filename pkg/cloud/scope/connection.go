@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+const (
+	// iapSourceRange is the range IAP TCP forwarding connects to the instances from.
+	iapSourceRange = "35.235.240.0/20"
+	sshPort        = 22
+)
+
+// ConnectionStrategy returns the strategy used to reach the builder instance.
+func (s *BuildScope) ConnectionStrategy() infrav1.ConnectionStrategy {
+	if s.GCPBuild.Spec.Connection != nil && s.GCPBuild.Spec.Connection.Strategy != nil {
+		return *s.GCPBuild.Spec.Connection.Strategy
+	}
+	if ptr.Deref(s.GCPBuild.Spec.PublicIP, false) {
+		return infrav1.PublicIPConnectionStrategy
+	}
+
+	return infrav1.InternalIPConnectionStrategy
+}
+
+// InstanceHost returns the address the builder instance is reached at with the connection strategy,
+// empty until the address is assigned.
+func (s *BuildScope) InstanceHost(instance *compute.Instance) string {
+	if len(instance.NetworkInterfaces) == 0 {
+		return ""
+	}
+	iface := instance.NetworkInterfaces[0]
+
+	if s.ConnectionStrategy() == infrav1.PublicIPConnectionStrategy {
+		if len(iface.AccessConfigs) == 0 {
+			return ""
+		}
+		return iface.AccessConfigs[0].NatIP
+	}
+
+	// IAP tunnels connect to nic0 as well, its internal IP is published for reference.
+	return iface.NetworkIP
+}
+
+// connectionPort returns the port of the SSH or WinRM server of the builder instance.
+func (s *BuildScope) connectionPort() int {
+	if s.IsWindows() {
+		return winRMPort
+	}
+
+	return sshPort
+}
+
+// IAPFirewallRuleSpec returns the firewall rule allowing IAP TCP forwarding to reach the builder instance.
+func (s *BuildScope) IAPFirewallRuleSpec() *compute.Firewall {
+	return &compute.Firewall{
+		Name:    fmt.Sprintf("allow-%s-iap", s.Name()),
+		Network: s.NetworkLink(),
+		Allowed: []*compute.FirewallAllowed{
+			{
+				IPProtocol: "TCP",
+				Ports: []string{
+					strconv.Itoa(s.connectionPort()),
+				},
+			},
+		},
+		Direction: "INGRESS",
+		SourceRanges: []string{
+			iapSourceRange,
+		},
+		TargetTags: []string{
+			fmt.Sprintf("%s-%s", s.Name(), "forge-builder"),
+		},
+	}
+}
+
+// ensureIAPTunnelCredentials adds the details to open an IAP TCP forwarding tunnel to the credentials Secret.
+func (s *BuildScope) ensureIAPTunnelCredentials(ctx context.Context) error {
+	return s.patchCredentialsSecret(ctx, "IAP tunnel", map[string][]byte{
+		"proxyType":    []byte("iap-tunnel"),
+		"iapProject":   []byte(s.Project()),
+		"iapZone":      []byte(s.Zone()),
+		"iapInstance":  []byte(s.Name()),
+		"iapInterface": []byte("nic0"),
+		"iapPort":      []byte(strconv.Itoa(s.connectionPort())),
+	})
+}
+
+// patchCredentialsSecret adds connection details to the credentials Secret.
+func (s *BuildScope) patchCredentialsSecret(ctx context.Context, kind string, data map[string][]byte) error {
+	ref := s.GCPBuild.Spec.SSHCredentialsRef
+	if ref == nil {
+		return errors.Errorf("no credentials secret to add the %s connection details to", kind)
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = s.GCPBuild.Namespace
+	}
+	if err := s.client.Get(ctx, key, secret); err != nil {
+		return errors.Wrapf(err, "failed to retrieve credentials secret %s", key)
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for k, v := range data {
+		secret.Data[k] = v
+	}
+
+	return errors.Wrapf(s.client.Patch(ctx, secret, patch), "failed to add %s connection details to secret %s", kind, key)
+}
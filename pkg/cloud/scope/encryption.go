@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+// encryptionKeys returns all the encryption keys referenced by the GCPBuild.
+func (s *BuildScope) encryptionKeys() []*infrav1.CustomerEncryptionKey {
+	keys := []*infrav1.CustomerEncryptionKey{
+		s.GCPBuild.Spec.RootDeviceEncryptionKey,
+		s.GCPBuild.Spec.ImageEncryptionKey,
+	}
+	for _, disk := range s.GCPBuild.Spec.AdditionalDisks {
+		keys = append(keys, disk.EncryptionKey)
+	}
+
+	return keys
+}
+
+// ResolveEncryptionKeys loads the customer-supplied encryption keys that are referenced from Secrets.
+func (s *BuildScope) ResolveEncryptionKeys(ctx context.Context) error {
+	s.suppliedKeys = map[string]string{}
+	for _, key := range s.encryptionKeys() {
+		if key == nil || key.SuppliedKey == nil {
+			continue
+		}
+
+		for _, ref := range []*corev1.SecretKeySelector{key.SuppliedKey.RawKeySecretRef, key.SuppliedKey.RSAEncryptedKeySecretRef} {
+			if ref == nil {
+				continue
+			}
+
+			secret := &corev1.Secret{}
+			secretKey := types.NamespacedName{Namespace: s.GCPBuild.Namespace, Name: ref.Name}
+			if err := s.client.Get(ctx, secretKey, secret); err != nil {
+				return errors.Wrapf(err, "failed to retrieve encryption key secret %s", secretKey)
+			}
+
+			value, ok := secret.Data[ref.Key]
+			if !ok {
+				return errors.Errorf("encryption key secret %s has no key %q", secretKey, ref.Key)
+			}
+			s.suppliedKeys[suppliedKeyID(ref)] = strings.TrimSpace(string(value))
+		}
+	}
+
+	return nil
+}
+
+// CustomerEncryptionKeySpec returns compute encryption key spec for the given key.
+func (s *BuildScope) CustomerEncryptionKeySpec(key *infrav1.CustomerEncryptionKey) *compute.CustomerEncryptionKey {
+	if key == nil {
+		return nil
+	}
+
+	encryptionKey := &compute.CustomerEncryptionKey{
+		KmsKeyServiceAccount: ptr.Deref(key.KMSKeyServiceAccount, ""),
+	}
+
+	switch key.KeyType {
+	case infrav1.CustomerManagedKey:
+		if key.ManagedKey == nil {
+			return nil
+		}
+		encryptionKey.KmsKeyName = key.ManagedKey.KMSKeyName
+	case infrav1.CustomerSuppliedKey:
+		if key.SuppliedKey == nil {
+			return nil
+		}
+		switch {
+		case len(key.SuppliedKey.RawKey) > 0:
+			encryptionKey.RawKey = base64.StdEncoding.EncodeToString(key.SuppliedKey.RawKey)
+		case len(key.SuppliedKey.RSAEncryptedKey) > 0:
+			encryptionKey.RsaEncryptedKey = base64.StdEncoding.EncodeToString(key.SuppliedKey.RSAEncryptedKey)
+		case key.SuppliedKey.RawKeySecretRef != nil:
+			encryptionKey.RawKey = s.suppliedKeys[suppliedKeyID(key.SuppliedKey.RawKeySecretRef)]
+		case key.SuppliedKey.RSAEncryptedKeySecretRef != nil:
+			encryptionKey.RsaEncryptedKey = s.suppliedKeys[suppliedKeyID(key.SuppliedKey.RSAEncryptedKeySecretRef)]
+		}
+	default:
+		return nil
+	}
+
+	return encryptionKey
+}
+
+// isCustomerSuppliedKey returns true if the given key is a customer-supplied encryption key.
+func isCustomerSuppliedKey(key *infrav1.CustomerEncryptionKey) bool {
+	return key != nil && key.KeyType == infrav1.CustomerSuppliedKey
+}
+
+func suppliedKeyID(ref *corev1.SecretKeySelector) string {
+	return ref.Name + "/" + ref.Key
+}
@@ -0,0 +1,413 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+const (
+	defaultImageNameTemplate = "forge-{{ .BuildName }}-{{ .Timestamp }}"
+	// defaultSnapshotNameTemplate follows the naming of scheduled snapshots.
+	defaultSnapshotNameTemplate = "forge-{{ .BuildName }}-{{ .Zone }}-{{ .Timestamp }}"
+
+	imageNameMaxLength = 63
+)
+
+var invalidImageNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// imageNameParams are the fields available in the output name template.
+type imageNameParams struct {
+	BuildName string
+	Timestamp string
+	UID       string
+	SpecHash  string
+	Zone      string
+}
+
+// ArtifactType returns the kind of artifact produced by the build.
+func (s *BuildScope) ArtifactType() infrav1.ArtifactType {
+	return ptr.Deref(s.GCPBuild.Spec.ArtifactType, infrav1.ImageArtifactType)
+}
+
+// ArtifactName returns the name of the produced artifact, empty until EnsureArtifactName is called.
+func (s *BuildScope) ArtifactName() string {
+	if s.GCPBuild.Status.Artifact != nil {
+		return s.GCPBuild.Status.Artifact.Name
+	}
+	// Builds started before typed artifact references only recorded the image name.
+	if s.GCPBuild.Status.Image != nil {
+		return s.GCPBuild.Status.Image.Name
+	}
+
+	return ""
+}
+
+// SetArtifactReady records the self link of the produced artifact and sets the artifact reference.
+func (s *BuildScope) SetArtifactReady(selfLink string) {
+	artifact := s.ArtifactReference()
+	artifact.SelfLink = ptr.To(selfLink)
+	s.GCPBuild.Status.Artifact = artifact
+
+	collection := "images"
+	switch artifact.Type {
+	case infrav1.MachineImageArtifactType:
+		collection = "machineImages"
+	case infrav1.SnapshotArtifactType:
+		collection = "snapshots"
+	}
+	s.SetArtifactRef(fmt.Sprintf("projects/%s/global/%s/%s", s.Project(), collection, artifact.Name))
+	conditions.MarkTrue(s.GCPBuild, infrav1.ImageCreatedCondition)
+}
+
+// ArtifactReference returns the typed reference of the produced artifact.
+func (s *BuildScope) ArtifactReference() *infrav1.ArtifactReference {
+	if s.GCPBuild.Status.Artifact != nil {
+		return s.GCPBuild.Status.Artifact.DeepCopy()
+	}
+
+	return &infrav1.ArtifactReference{
+		Type: s.ArtifactType(),
+		Name: s.ArtifactName(),
+	}
+}
+
+// ImageFamily returns the image family the produced image is published into.
+func (s *BuildScope) ImageFamily() *string {
+	if s.GCPBuild.Spec.Output == nil {
+		return nil
+	}
+
+	return s.GCPBuild.Spec.Output.Family
+}
+
+// ImageRetentionPolicy returns the retention policy for older images of the image family.
+func (s *BuildScope) ImageRetentionPolicy() *infrav1.ImageRetentionPolicy {
+	if s.GCPBuild.Spec.Output == nil {
+		return nil
+	}
+
+	return s.GCPBuild.Spec.Output.Retention
+}
+
+// IsImageRetentionStarted returns true once the retention policy is being applied to the image family.
+func (s *BuildScope) IsImageRetentionStarted() bool {
+	return conditions.Has(s.GCPBuild, infrav1.ImageRetentionAppliedCondition)
+}
+
+// IsImageRetentionFailed returns true if the retention policy could not be fully applied to the image family.
+func (s *BuildScope) IsImageRetentionFailed() bool {
+	return conditions.GetReason(s.GCPBuild, infrav1.ImageRetentionAppliedCondition) == infrav1.ImageRetentionFailedReason
+}
+
+// ImageExports returns the Cloud Storage destinations the produced image is exported to.
+func (s *BuildScope) ImageExports() []infrav1.ImageExport {
+	if s.GCPBuild.Spec.Output == nil {
+		return nil
+	}
+
+	return s.GCPBuild.Spec.Output.Exports
+}
+
+// ImageExportStatuses returns the status of the exports of the produced image.
+func (s *BuildScope) ImageExportStatuses() []infrav1.ImageExportStatus {
+	return s.GCPBuild.Status.Exports
+}
+
+// SetImageExportStatus adds or updates the status of the export to the given URI.
+func (s *BuildScope) SetImageExportStatus(status infrav1.ImageExportStatus) {
+	for i := range s.GCPBuild.Status.Exports {
+		if s.GCPBuild.Status.Exports[i].URI == status.URI {
+			s.GCPBuild.Status.Exports[i] = status
+			return
+		}
+	}
+
+	s.GCPBuild.Status.Exports = append(s.GCPBuild.Status.Exports, status)
+}
+
+// ImageCopies returns the copies of the produced image in other projects.
+func (s *BuildScope) ImageCopies() []infrav1.ImageCopy {
+	if s.GCPBuild.Spec.Output == nil {
+		return nil
+	}
+
+	return s.GCPBuild.Spec.Output.CopyTo
+}
+
+// ImageCopyStatuses returns the status of the copies of the produced image.
+func (s *BuildScope) ImageCopyStatuses() []infrav1.ImageCopyStatus {
+	return s.GCPBuild.Status.Copies
+}
+
+// SetImageCopyStatus adds or updates the status of the copy in the given project.
+func (s *BuildScope) SetImageCopyStatus(status infrav1.ImageCopyStatus) {
+	for i := range s.GCPBuild.Status.Copies {
+		if s.GCPBuild.Status.Copies[i].Project == status.Project && s.GCPBuild.Status.Copies[i].Name == status.Name {
+			s.GCPBuild.Status.Copies[i] = status
+			return
+		}
+	}
+
+	s.GCPBuild.Status.Copies = append(s.GCPBuild.Status.Copies, status)
+}
+
+// ImageCopySpec returns compute image spec for the copy of the given source image.
+// Copies are encrypted with Google-managed keys.
+func (s *BuildScope) ImageCopySpec(target infrav1.ImageCopy, source *compute.Image) *compute.Image {
+	image := &compute.Image{
+		Name:            ptr.Deref(target.Name, source.Name),
+		Family:          ptr.Deref(target.Family, ""),
+		SourceImage:     source.SelfLink,
+		Description:     source.Description,
+		Architecture:    source.Architecture,
+		GuestOsFeatures: source.GuestOsFeatures,
+		Labels:          source.Labels,
+	}
+
+	// Reading an image encrypted with a customer-supplied key requires the key.
+	if isCustomerSuppliedKey(s.GCPBuild.Spec.ImageEncryptionKey) {
+		image.SourceImageEncryptionKey = s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.ImageEncryptionKey)
+	}
+
+	return image
+}
+
+// TargetComputeService returns a compute service using the given credentials,
+// or the compute service of the build when no credentials are given.
+func (s *BuildScope) TargetComputeService(ctx context.Context, credentialsRef *corev1.SecretReference) (*compute.Service, error) {
+	if credentialsRef == nil {
+		return s.GetComputeService(), nil
+	}
+
+	return newComputeService(ctx, credentialsRef, s.client)
+}
+
+// ImageUsers returns the principals granted access to the produced image.
+func (s *BuildScope) ImageUsers() []string {
+	return s.GCPBuild.Spec.ImageUsers
+}
+
+// SetImageIAMBindings reflects the given IAM bindings of the produced image in the status.
+func (s *BuildScope) SetImageIAMBindings(bindings []*compute.Binding) {
+	if s.GCPBuild.Status.Image == nil {
+		return
+	}
+
+	s.GCPBuild.Status.Image.IAMBindings = nil
+	for _, binding := range bindings {
+		s.GCPBuild.Status.Image.IAMBindings = append(s.GCPBuild.Status.Image.IAMBindings, infrav1.IAMBinding{
+			Role:    binding.Role,
+			Members: binding.Members,
+		})
+	}
+}
+
+// EnsureArtifactName renders the name of the produced artifact from the output name template.
+// The name is rendered only once and kept in the status, so that it stays stable across reconciles.
+func (s *BuildScope) EnsureArtifactName() (string, error) {
+	if name := s.ArtifactName(); name != "" {
+		return name, nil
+	}
+
+	nameTemplate := defaultImageNameTemplate
+	if s.ArtifactType() == infrav1.SnapshotArtifactType {
+		nameTemplate = defaultSnapshotNameTemplate
+	}
+	if s.GCPBuild.Spec.Output != nil {
+		nameTemplate = ptr.Deref(s.GCPBuild.Spec.Output.NameTemplate, nameTemplate)
+	}
+
+	tmpl, err := template.New("imageName").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse image name template")
+	}
+
+	specHash, err := s.specHash()
+	if err != nil {
+		return "", err
+	}
+
+	params := imageNameParams{
+		BuildName: s.Name(),
+		Timestamp: time.Now().UTC().Format("20060102150405"),
+		UID:       shorten(string(s.GCPBuild.UID), 8),
+		SpecHash:  shorten(specHash, 8),
+		Zone:      s.Zone(),
+	}
+	rendered, err := renderImageName(tmpl, params)
+	if err != nil {
+		return "", err
+	}
+
+	// Shorten the build name rather than truncating the rendered name, which would cut the suffix
+	// that keeps the names unique, e.g. the timestamp.
+	if overflow := len(rendered) - imageNameMaxLength; overflow > 0 {
+		params.BuildName = strings.TrimRight(shorten(params.BuildName, max(len(params.BuildName)-overflow, 0)), "-")
+		if rendered, err = renderImageName(tmpl, params); err != nil {
+			return "", err
+		}
+	}
+
+	name := sanitizeImageName(rendered)
+	if name == "" {
+		return "", errors.Errorf("image name template %q rendered an empty name", nameTemplate)
+	}
+
+	s.GCPBuild.Status.Artifact = &infrav1.ArtifactReference{
+		Type: s.ArtifactType(),
+		Name: name,
+	}
+
+	return name, nil
+}
+
+// MachineImageSpec returns compute machine image spec for the machine image captured from the given instance.
+func (s *BuildScope) MachineImageSpec(instance *compute.Instance) *compute.MachineImage {
+	output := s.outputSpec()
+
+	machineImage := &compute.MachineImage{
+		Name:                      s.ArtifactName(),
+		SourceInstance:            instance.SelfLink,
+		Description:               ptr.Deref(output.Description, fmt.Sprintf("Custom machine image created from instance: %s", s.Name())),
+		StorageLocations:          output.StorageLocations,
+		MachineImageEncryptionKey: s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.ImageEncryptionKey),
+	}
+
+	// Reading disks encrypted with customer-supplied keys requires the keys. The disks of the
+	// instance are attached in order, the boot disk first.
+	keys := []*infrav1.CustomerEncryptionKey{s.GCPBuild.Spec.RootDeviceEncryptionKey}
+	for _, disk := range s.GCPBuild.Spec.AdditionalDisks {
+		keys = append(keys, disk.EncryptionKey)
+	}
+	for i, disk := range instance.Disks {
+		if i >= len(keys) || disk.Source == "" || !isCustomerSuppliedKey(keys[i]) {
+			continue
+		}
+		machineImage.SourceDiskEncryptionKeys = append(machineImage.SourceDiskEncryptionKeys, &compute.SourceDiskEncryptionKey{
+			SourceDisk:        disk.Source,
+			DiskEncryptionKey: s.CustomerEncryptionKeySpec(keys[i]),
+		})
+	}
+
+	return machineImage
+}
+
+// SnapshotSpec returns compute snapshot spec for the snapshot captured from the instance boot disk.
+func (s *BuildScope) SnapshotSpec() *compute.Snapshot {
+	output := s.outputSpec()
+
+	snapshot := &compute.Snapshot{
+		Name:                  s.ArtifactName(),
+		SourceDisk:            s.sourceDisk(),
+		Description:           ptr.Deref(output.Description, fmt.Sprintf("Custom snapshot created from instance: %s", s.Name())),
+		StorageLocations:      output.StorageLocations,
+		Labels:                s.outputLabels(),
+		SnapshotEncryptionKey: s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.ImageEncryptionKey),
+	}
+
+	// Reading a boot disk encrypted with a customer-supplied key requires the key.
+	if isCustomerSuppliedKey(s.GCPBuild.Spec.RootDeviceEncryptionKey) {
+		snapshot.SourceDiskEncryptionKey = s.CustomerEncryptionKeySpec(s.GCPBuild.Spec.RootDeviceEncryptionKey)
+	}
+
+	return snapshot
+}
+
+// outputSpec returns the output spec, or an empty one when not set.
+func (s *BuildScope) outputSpec() *infrav1.OutputSpec {
+	if s.GCPBuild.Spec.Output == nil {
+		return &infrav1.OutputSpec{}
+	}
+
+	return s.GCPBuild.Spec.Output
+}
+
+// outputLabels returns the labels of the produced artifact.
+func (s *BuildScope) outputLabels() infrav1.Labels {
+	return infrav1.Build(infrav1.BuildParams{
+		BuildName:  s.Name(),
+		Lifecycle:  infrav1.ResourceLifecycleOwned,
+		Additional: infrav1.Labels{}.AddLabels(s.AdditionalLabels()).AddLabels(s.outputSpec().Labels),
+	})
+}
+
+// sourceDisk returns the URL of the boot disk of the instance.
+func (s *BuildScope) sourceDisk() string {
+	return fmt.Sprintf("projects/%s/zones/%s/disks/%s", s.Project(), s.Zone(), s.Name())
+}
+
+// specHash returns the hex encoded SHA-256 hash of the GCPBuild spec, ignoring the fields
+// set by the controller.
+func (s *BuildScope) specHash() (string, error) {
+	spec := s.GCPBuild.Spec.DeepCopy()
+	spec.InstanceID = nil
+	spec.SSHCredentialsRef = nil
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to hash GCPBuild spec")
+	}
+	sum := sha256.Sum256(raw)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sanitizeImageName converts the given name to a valid GCP image name.
+func sanitizeImageName(name string) string {
+	name = invalidImageNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.TrimLeft(name, "-0123456789")
+	if len(name) > imageNameMaxLength {
+		name = name[:imageNameMaxLength]
+	}
+
+	return strings.TrimRight(name, "-")
+}
+
+// renderImageName renders the image name template with the given parameters.
+func renderImageName(tmpl *template.Template, params imageNameParams) (string, error) {
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, params); err != nil {
+		return "", errors.Wrap(err, "failed to render image name template")
+	}
+
+	return builder.String(), nil
+}
+
+func shorten(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+
+	return value
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"strings"
+	"testing"
+
+	buildv1 "github.com/forge-build/forge/pkg/api/v1alpha1"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+func newImageScope(spec infrav1.GCPBuildSpec, status infrav1.GCPBuildStatus) *BuildScope {
+	return &BuildScope{
+		Build: &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "my-build"}},
+		GCPBuild: &infrav1.GCPBuild{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-build", UID: "0123abcd-ef45-6789"},
+			Spec:       spec,
+			Status:     status,
+		},
+	}
+}
+
+func TestEnsureArtifactName(t *testing.T) {
+	tests := []struct {
+		name      string
+		buildName string
+		spec      infrav1.GCPBuildSpec
+		status    infrav1.GCPBuildStatus
+		want      string
+		match     string
+		wantErr   bool
+	}{
+		{
+			name:  "default image name",
+			match: `^forge-my-build-\d{14}$`,
+		},
+		{
+			name: "default snapshot name",
+			spec: infrav1.GCPBuildSpec{
+				ArtifactType: ptr.To(infrav1.SnapshotArtifactType),
+				Zone:         "europe-west1-b",
+			},
+			match: `^forge-my-build-europe-west1-b-\d{14}$`,
+		},
+		{
+			name: "template with the uid",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("{{ .BuildName }}-{{ .UID }}")},
+			},
+			want: "my-build-0123abcd",
+		},
+		{
+			name: "template with the spec hash",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("base-{{ .SpecHash }}")},
+			},
+			match: `^base-[0-9a-f]{8}$`,
+		},
+		{
+			name:      "long build name is shortened to keep the timestamp",
+			buildName: "my-very-long-build-name-that-does-not-fit-in-an-image-name",
+			match:     `^forge-my-very-long-build-name-that-does-not-fit-\d{14}$`,
+		},
+		{
+			name:      "long build name is shortened to keep the uid",
+			buildName: "my-very-long-build-name-that-does-not-fit-in-an-image-name",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("{{ .BuildName }}-{{ .UID }}")},
+			},
+			want: "my-very-long-build-name-that-does-not-fit-in-an-image-0123abcd",
+		},
+		{
+			name: "rendered name is sanitized",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("Ubuntu_22.04-{{ .BuildName }}")},
+			},
+			want: "ubuntu-22-04-my-build",
+		},
+		{
+			name: "name recorded in the status is kept",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("{{ .BuildName }}-{{ .UID }}")},
+			},
+			status: infrav1.GCPBuildStatus{
+				Artifact: &infrav1.ArtifactReference{Type: infrav1.ImageArtifactType, Name: "forge-my-build-20240101000000"},
+			},
+			want: "forge-my-build-20240101000000",
+		},
+		{
+			name: "image name of builds without artifact reference is kept",
+			status: infrav1.GCPBuildStatus{
+				Image: &infrav1.ImageStatus{Name: "forge-my-build-20230101000000"},
+			},
+			want: "forge-my-build-20230101000000",
+		},
+		{
+			name: "invalid template",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("{{ .BuildName ")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown field",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("{{ .Version }}")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty name",
+			spec: infrav1.GCPBuildSpec{
+				Output: &infrav1.OutputSpec{NameTemplate: ptr.To("2024-")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newImageScope(tt.spec, tt.status)
+			if tt.buildName != "" {
+				s.Build.Name = tt.buildName
+			}
+
+			name, err := s.EnsureArtifactName()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(s.GCPBuild.Status.Artifact).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.match != "" {
+				g.Expect(name).To(MatchRegexp(tt.match))
+			} else {
+				g.Expect(name).To(Equal(tt.want))
+			}
+
+			// The name is rendered once and stays stable across reconciles.
+			g.Expect(s.ArtifactName()).To(Equal(name))
+			g.Expect(s.EnsureArtifactName()).To(Equal(name))
+		})
+	}
+}
+
+func TestSanitizeImageName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "valid name",
+			input: "forge-my-build-20240101000000",
+			want:  "forge-my-build-20240101000000",
+		},
+		{
+			name:  "upper case",
+			input: "Forge-My-Build",
+			want:  "forge-my-build",
+		},
+		{
+			name:  "invalid characters",
+			input: "ubuntu_22.04 lts",
+			want:  "ubuntu-22-04-lts",
+		},
+		{
+			name:  "runs of invalid characters",
+			input: "a__b..c",
+			want:  "a-b-c",
+		},
+		{
+			name:  "leading digits and dashes",
+			input: "-2024-image",
+			want:  "image",
+		},
+		{
+			name:  "trailing dashes",
+			input: "image--",
+			want:  "image",
+		},
+		{
+			name:  "too long",
+			input: strings.Repeat("a", 70),
+			want:  strings.Repeat("a", 63),
+		},
+		{
+			name:  "too long ending with a dash",
+			input: strings.Repeat("a", 62) + "-b",
+			want:  strings.Repeat("a", 62),
+		},
+		{
+			name:  "nothing valid",
+			input: "123_",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(sanitizeImageName(tt.input)).To(Equal(tt.want))
+		})
+	}
+}
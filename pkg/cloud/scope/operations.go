@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"path"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+// Operation returns the running operation of the target, nil if there is none.
+func (s *BuildScope) Operation(target string) *infrav1.OperationStatus {
+	for i := range s.GCPBuild.Status.Operations {
+		if s.GCPBuild.Status.Operations[i].Target == target {
+			return &s.GCPBuild.Status.Operations[i]
+		}
+	}
+
+	return nil
+}
+
+// Operations returns the running operations.
+func (s *BuildScope) Operations() []infrav1.OperationStatus {
+	return s.GCPBuild.Status.Operations
+}
+
+// SetOperation records the running operation of the target.
+func (s *BuildScope) SetOperation(target string, op *compute.Operation) {
+	status := infrav1.OperationStatus{Target: target, Name: op.Name}
+	if project := operationProject(op.SelfLink); project != "" && project != s.Project() {
+		status.Project = project
+	}
+	if op.Zone != "" {
+		status.Zone = path.Base(op.Zone)
+	}
+	if op.Region != "" {
+		status.Region = path.Base(op.Region)
+	}
+
+	if existing := s.Operation(target); existing != nil {
+		*existing = status
+		return
+	}
+	s.GCPBuild.Status.Operations = append(s.GCPBuild.Status.Operations, status)
+}
+
+// ClearOperation forgets the operation of the target once it is done.
+func (s *BuildScope) ClearOperation(target string) {
+	operations := s.GCPBuild.Status.Operations[:0]
+	for _, op := range s.GCPBuild.Status.Operations {
+		if op.Target != target {
+			operations = append(operations, op)
+		}
+	}
+	s.GCPBuild.Status.Operations = operations
+}
+
+// operationProject returns the project of an operation from its self link, empty if it can't be found.
+func operationProject(selfLink string) string {
+	_, rest, ok := strings.Cut(selfLink, "projects/")
+	if !ok {
+		return ""
+	}
+	project, _, _ := strings.Cut(rest, "/")
+
+	return project
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/compute/v1"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+func TestOperations(t *testing.T) {
+	g := NewWithT(t)
+	s := &BuildScope{GCPBuild: &infrav1.GCPBuild{Spec: infrav1.GCPBuildSpec{Project: "my-project"}}}
+
+	g.Expect(s.Operation("insert instances/my-build")).To(BeNil())
+
+	s.SetOperation("insert instances/my-build", &compute.Operation{
+		Name: "op-1",
+		Zone: "https://www.googleapis.com/compute/v1/projects/my-project/zones/europe-west1-b",
+	})
+	s.SetOperation("insert subnetworks/my-subnet", &compute.Operation{
+		Name:   "op-2",
+		Region: "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1",
+	})
+	s.SetOperation("insert networks/my-network", &compute.Operation{Name: "op-3"})
+	g.Expect(s.Operations()).To(Equal([]infrav1.OperationStatus{
+		{Target: "insert instances/my-build", Name: "op-1", Zone: "europe-west1-b"},
+		{Target: "insert subnetworks/my-subnet", Name: "op-2", Region: "europe-west1"},
+		{Target: "insert networks/my-network", Name: "op-3"},
+	}))
+
+	// A new operation of the target replaces the previous one.
+	s.SetOperation("insert instances/my-build", &compute.Operation{
+		Name: "op-4",
+		Zone: "https://www.googleapis.com/compute/v1/projects/my-project/zones/europe-west1-c",
+	})
+	g.Expect(s.Operation("insert instances/my-build")).To(Equal(&infrav1.OperationStatus{
+		Target: "insert instances/my-build", Name: "op-4", Zone: "europe-west1-c",
+	}))
+	g.Expect(s.Operations()).To(HaveLen(3))
+
+	// Operations of other projects record their project, read from their self link.
+	s.SetOperation("insert projects/other-project/images/my-image", &compute.Operation{
+		Name:     "op-5",
+		SelfLink: "https://www.googleapis.com/compute/v1/projects/other-project/global/operations/op-5",
+	})
+	s.SetOperation("insert images/my-image", &compute.Operation{
+		Name:     "op-6",
+		SelfLink: "https://www.googleapis.com/compute/v1/projects/my-project/global/operations/op-6",
+	})
+	g.Expect(s.Operation("insert projects/other-project/images/my-image")).To(Equal(&infrav1.OperationStatus{
+		Target: "insert projects/other-project/images/my-image", Name: "op-5", Project: "other-project",
+	}))
+	g.Expect(s.Operation("insert images/my-image")).To(Equal(&infrav1.OperationStatus{
+		Target: "insert images/my-image", Name: "op-6",
+	}))
+	s.ClearOperation("insert projects/other-project/images/my-image")
+	s.ClearOperation("insert images/my-image")
+
+	s.ClearOperation("insert subnetworks/my-subnet")
+	g.Expect(s.Operation("insert subnetworks/my-subnet")).To(BeNil())
+	g.Expect(s.Operations()).To(HaveLen(2))
+
+	// Clearing a target without operation is a no-op.
+	s.ClearOperation("delete firewalls/my-firewall")
+	g.Expect(s.Operations()).To(HaveLen(2))
+}
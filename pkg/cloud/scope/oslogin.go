@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+// IsOSLogin returns true if the SSH key is authorized on the builder instance through OS Login.
+func (s *BuildScope) IsOSLogin() bool {
+	if s.GCPBuild.Spec.Connection == nil {
+		return false
+	}
+
+	return ptr.Deref(s.GCPBuild.Spec.Connection.Mode, infrav1.MetadataConnectionMode) == infrav1.OSLoginConnectionMode
+}
+
+// ConnectionUsername returns the username used to connect to the builder instance,
+// which is the POSIX username of the OS Login profile once the key is imported.
+func (s *BuildScope) ConnectionUsername() string {
+	if s.IsOSLogin() && s.GCPBuild.Status.OSLogin != nil {
+		return s.GCPBuild.Status.OSLogin.Username
+	}
+
+	return s.GCPBuild.Spec.Username
+}
+
+// SSHPublicKey returns the public key used to connect to the builder instance.
+func (s *BuildScope) SSHPublicKey() string {
+	return strings.TrimSuffix(s.sshKEy.PublicKey, "\n")
+}
+
+// OSLoginProfile returns the OS Login profile the SSH key is imported in.
+func (s *BuildScope) OSLoginProfile() *infrav1.OSLoginStatus {
+	return s.GCPBuild.Status.OSLogin
+}
+
+// SetOSLoginProfile sets the OS Login profile the SSH key is imported in.
+func (s *BuildScope) SetOSLoginProfile(profile *infrav1.OSLoginStatus) {
+	s.GCPBuild.Status.OSLogin = profile
+}
+
+// ServiceAccountEmail returns the email of the service account the controller authenticates with,
+// which owns the OS Login profile. It is read from the referenced credentials, the application default
+// credentials or, when running on GCE, the metadata server.
+func (s *BuildScope) ServiceAccountEmail(ctx context.Context) (string, error) {
+	var rawData []byte
+	if s.GCPBuild.Spec.CredentialsRef != nil {
+		data, err := getCredentialDataFromRef(ctx, s.GCPBuild.Spec.CredentialsRef, s.client)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get gcp credentials")
+		}
+		rawData = data
+	} else if creds, err := google.FindDefaultCredentials(ctx); err == nil {
+		rawData = creds.JSON
+	}
+
+	if len(rawData) > 0 {
+		var creds struct {
+			ClientEmail string `json:"client_email"`
+		}
+		if err := json.Unmarshal(rawData, &creds); err != nil {
+			return "", errors.Wrap(err, "failed to parse gcp credentials")
+		}
+		if creds.ClientEmail != "" {
+			return creds.ClientEmail, nil
+		}
+	}
+
+	if !metadata.OnGCE() {
+		return "", errors.New("unable to find the service account email, OS Login requires service account credentials")
+	}
+
+	email, err := metadata.EmailWithContext(ctx, "default")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the service account email from the metadata server")
+	}
+
+	return email, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+// defaultReadinessTimeout is the time a builder instance has to become ready after its creation.
+const defaultReadinessTimeout = 10 * time.Minute
+
+// ReadinessGuestAttribute returns the query path of the guest attribute the builder instance must expose
+// before it is ready, empty if the instance is ready once RUNNING.
+func (s *BuildScope) ReadinessGuestAttribute() string {
+	if s.GCPBuild.Spec.Readiness == nil || s.GCPBuild.Spec.Readiness.GuestAttribute == nil {
+		return ""
+	}
+
+	return *s.GCPBuild.Spec.Readiness.GuestAttribute
+}
+
+// ReadinessTimeout returns the time the builder instance has to become ready after its creation.
+func (s *BuildScope) ReadinessTimeout() time.Duration {
+	if s.GCPBuild.Spec.Readiness == nil || s.GCPBuild.Spec.Readiness.Timeout == nil {
+		return defaultReadinessTimeout
+	}
+
+	return s.GCPBuild.Spec.Readiness.Timeout.Duration
+}
+
+// IsInstanceReady returns true once the builder instance passed the readiness gate.
+func (s *BuildScope) IsInstanceReady() bool {
+	return conditions.IsTrue(s.GCPBuild, infrav1.InstanceReadyCondition)
+}
+
+// MarkInstanceReady reports that the builder instance is ready to accept connections.
+func (s *BuildScope) MarkInstanceReady() {
+	conditions.MarkTrue(s.GCPBuild, infrav1.InstanceReadyCondition)
+}
+
+// MarkInstanceNotReady reports what the readiness gate of the builder instance is waiting on.
+func (s *BuildScope) MarkInstanceNotReady(reason, message string) {
+	severity := clusterv1.ConditionSeverityInfo
+	if reason == infrav1.InstanceReadinessTimeoutReason {
+		severity = clusterv1.ConditionSeverityError
+	}
+	conditions.MarkFalse(s.GCPBuild, infrav1.InstanceReadyCondition, reason, severity, "%s", message)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+const (
+	serialOutputConfigMapFn = "%s-serial-output"
+	serialOutputDataKey     = "serial-port-1.log"
+	// serialOutputMaxBytes bounds the tail of the serial port output kept in the ConfigMap.
+	serialOutputMaxBytes = 64 * 1024
+	// serialOutputInterval is the minimum time between two captures of a running build.
+	serialOutputInterval = 30 * time.Second
+)
+
+// SerialOutputOffset returns the offset of the serial port output to read from.
+func (s *BuildScope) SerialOutputOffset() int64 {
+	if s.GCPBuild.Status.SerialOutput == nil {
+		return 0
+	}
+
+	return s.GCPBuild.Status.SerialOutput.Next
+}
+
+// ResetSerialOutputOffset reads the serial port output from the start, e.g. once the builder instance is recreated.
+func (s *BuildScope) ResetSerialOutputOffset() {
+	if s.GCPBuild.Status.SerialOutput != nil {
+		s.GCPBuild.Status.SerialOutput.Next = 0
+	}
+}
+
+// SerialOutputCaptureDue returns true if the serial port output was not captured recently.
+func (s *BuildScope) SerialOutputCaptureDue() bool {
+	status := s.GCPBuild.Status.SerialOutput
+	if status == nil || status.LastCaptureTime == nil {
+		return true
+	}
+
+	return time.Since(status.LastCaptureTime.Time) >= serialOutputInterval
+}
+
+// AppendSerialOutput appends serial port output to the ConfigMap owned by the GCPBuild, keeping a bounded tail,
+// and records the offset to read from on the next capture. The ConfigMap is written before the offset is persisted
+// with the status of the GCPBuild, if persisting the status fails the next capture appends the same output again.
+// Duplicated lines are accepted, the output is meant for troubleshooting.
+func (s *BuildScope) AppendSerialOutput(ctx context.Context, contents string, next int64) error {
+	key := types.NamespacedName{Namespace: s.GCPBuild.Namespace, Name: fmt.Sprintf(serialOutputConfigMapFn, s.GCPBuild.Name)}
+
+	configMap := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, key, configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to retrieve serial output configmap %s", key)
+	}
+
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data: map[string]string{
+				serialOutputDataKey: serialOutputTail(contents),
+			},
+		}
+		if err := controllerutil.SetControllerReference(s.GCPBuild, configMap, s.client.Scheme()); err != nil {
+			return errors.Wrap(err, "failed to set owner of serial output configmap")
+		}
+		if err := s.client.Create(ctx, configMap); err != nil {
+			return errors.Wrapf(err, "failed to create serial output configmap %s", key)
+		}
+	} else if contents != "" {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[serialOutputDataKey] = serialOutputTail(configMap.Data[serialOutputDataKey] + contents)
+		if err := s.client.Update(ctx, configMap); err != nil {
+			return errors.Wrapf(err, "failed to update serial output configmap %s", key)
+		}
+	}
+
+	now := metav1.Now()
+	s.GCPBuild.Status.SerialOutput = &infrav1.SerialOutputStatus{
+		ConfigMapRef:    corev1.LocalObjectReference{Name: key.Name},
+		Next:            next,
+		LastCaptureTime: &now,
+	}
+
+	return nil
+}
+
+// SerialOutputLines returns the last lines of the captured serial port output.
+func (s *BuildScope) SerialOutputLines(ctx context.Context, lines int) (string, error) {
+	status := s.GCPBuild.Status.SerialOutput
+	if status == nil {
+		return "", nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: s.GCPBuild.Namespace, Name: status.ConfigMapRef.Name}
+	if err := s.client.Get(ctx, key, configMap); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve serial output configmap %s", key)
+	}
+
+	output := strings.Split(strings.TrimRight(configMap.Data[serialOutputDataKey], "\n"), "\n")
+	if len(output) > lines {
+		output = output[len(output)-lines:]
+	}
+
+	return strings.Join(output, "\n"), nil
+}
+
+// serialOutputTail returns the last serialOutputMaxBytes of the output, starting at a line boundary.
+func serialOutputTail(output string) string {
+	output = strings.ToValidUTF8(output, "?")
+	if len(output) <= serialOutputMaxBytes {
+		return output
+	}
+
+	output = output[len(output)-serialOutputMaxBytes:]
+	if i := strings.IndexByte(output, '\n'); i >= 0 {
+		output = output[i+1:]
+	}
+
+	return output
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// minMaxRunDuration is the minimum maximum run duration GCP accepts for an instance.
+const minMaxRunDuration = 30 * time.Second
+
+// EnsureStartTime records the time the build started, once.
+func (s *BuildScope) EnsureStartTime() {
+	if s.GCPBuild.Status.StartTime == nil {
+		now := metav1.Now()
+		s.GCPBuild.Status.StartTime = &now
+	}
+}
+
+// Deadline returns the time the build has to complete by, and false if the build has no timeout or has not started.
+func (s *BuildScope) Deadline() (time.Time, bool) {
+	if s.GCPBuild.Spec.Timeout == nil || s.GCPBuild.Status.StartTime == nil {
+		return time.Time{}, false
+	}
+
+	return s.GCPBuild.Status.StartTime.Add(s.GCPBuild.Spec.Timeout.Duration), true
+}
+
+// HasTimedOut returns true if the deadline of the build has passed.
+func (s *BuildScope) HasTimedOut() bool {
+	deadline, ok := s.Deadline()
+
+	return ok && time.Now().After(deadline)
+}
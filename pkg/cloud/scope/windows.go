@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
+)
+
+const (
+	windowsKeysMetaKey = "windows-keys"
+	windowsKeyBits     = 2048
+	windowsKeyTTL      = time.Hour
+	windowsKeySecretFn = "%s-windows-key"
+	windowsKeyDataKey  = "privateKey"
+
+	// winRMPort is the port of the WinRM HTTPS listener enabled on GCE Windows images.
+	winRMPort = 5986
+)
+
+// windowsKey is the windows-keys metadata entry asking the guest agent to set a password.
+type windowsKey struct {
+	UserName string `json:"userName"`
+	Modulus  string `json:"modulus"`
+	Exponent string `json:"exponent"`
+	Email    string `json:"email"`
+	ExpireOn string `json:"expireOn"`
+}
+
+// IsWindows returns true if the builder instance runs Windows.
+func (s *BuildScope) IsWindows() bool {
+	return ptr.Deref(s.GCPBuild.Spec.OSType, infrav1.LinuxOSType) == infrav1.WindowsOSType
+}
+
+// EnsureWindowsKey loads the RSA key used to exchange the Windows password with the guest agent,
+// generating it and storing it in a Secret owned by the GCPBuild on the first reconcile.
+func (s *BuildScope) EnsureWindowsKey(ctx context.Context) error {
+	if !s.IsWindows() {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: s.GCPBuild.Namespace, Name: fmt.Sprintf(windowsKeySecretFn, s.GCPBuild.Name)}
+	err := s.client.Get(ctx, key, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to retrieve windows key secret %s", key)
+	}
+
+	if apierrors.IsNotFound(err) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, windowsKeyBits)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate windows key")
+		}
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Type:       corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				windowsKeyDataKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}),
+			},
+		}
+		if err := controllerutil.SetControllerReference(s.GCPBuild, secret, s.client.Scheme()); err != nil {
+			return errors.Wrap(err, "failed to set owner of windows key secret")
+		}
+		if err := s.client.Create(ctx, secret); err != nil {
+			return errors.Wrapf(err, "failed to create windows key secret %s", key)
+		}
+	}
+
+	block, _ := pem.Decode(secret.Data[windowsKeyDataKey])
+	if block == nil {
+		return errors.Errorf("windows key secret %s has no valid %q", key, windowsKeyDataKey)
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse windows key of secret %s", key)
+	}
+	s.windowsKey = privateKey
+
+	return nil
+}
+
+// windowsKeysMetadata returns the value of the windows-keys metadata asking the guest agent
+// to create the user and set its password.
+func (s *BuildScope) windowsKeysMetadata() (string, error) {
+	if s.windowsKey == nil {
+		return "", errors.New("windows key is not loaded")
+	}
+
+	raw, err := json.Marshal(windowsKey{
+		UserName: s.GCPBuild.Spec.Username,
+		Modulus:  s.WindowsKeyModulus(),
+		Exponent: base64.StdEncoding.EncodeToString(big.NewInt(int64(s.windowsKey.E)).Bytes()),
+		ExpireOn: time.Now().Add(windowsKeyTTL).UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal windows key")
+	}
+
+	return string(raw), nil
+}
+
+// WindowsKeyModulus returns the base64 encoded modulus of the windows key, identifying the password
+// reported by the guest agent.
+func (s *BuildScope) WindowsKeyModulus() string {
+	if s.windowsKey == nil {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(s.windowsKey.N.Bytes())
+}
+
+// SetWindowsPassword decrypts the given base64 encoded password reported by the guest agent.
+func (s *BuildScope) SetWindowsPassword(encryptedPassword string) error {
+	if s.windowsKey == nil {
+		return errors.New("windows key is not loaded")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encryptedPassword)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode windows password")
+	}
+	// The windows-keys protocol encrypts the password with RSA-OAEP and SHA-1.
+	password, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, s.windowsKey, ciphertext, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt windows password")
+	}
+	s.windowsPassword = string(password)
+
+	return nil
+}
+
+// IsSysprepStarted returns true once GCESysprep has been started on the builder instance.
+func (s *BuildScope) IsSysprepStarted() bool {
+	return s.GCPBuild.Status.SysprepStarted
+}
+
+// SetSysprepStarted records that GCESysprep has been started on the builder instance.
+func (s *BuildScope) SetSysprepStarted() {
+	s.GCPBuild.Status.SysprepStarted = true
+}
+
+// ensureWinRMCredentials adds the WinRM connection details to the credentials Secret.
+func (s *BuildScope) ensureWinRMCredentials(ctx context.Context) error {
+	return s.patchCredentialsSecret(ctx, "WinRM", map[string][]byte{
+		"connectionType": []byte("winrm"),
+		"winrmPort":      []byte(strconv.Itoa(winRMPort)),
+		"winrmUseSSL":    []byte("true"),
+		// GCE Windows images use a self-signed certificate for the WinRM HTTPS listener.
+		"winrmInsecure": []byte("true"),
+	})
+}
@@ -27,11 +27,14 @@ import (
 
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/images"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/instances"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
 
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/firewalls"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/networks"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/subnets"
+	gcperrors "github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/errors"
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/oslogin"
 	"github.com/forge-build/forge-provider-gcp/pkg/cloud/scope"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -43,6 +46,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,15 +57,23 @@ import (
 	infrav1 "github.com/forge-build/forge-provider-gcp/pkg/api/v1alpha1"
 )
 
-const ControllerName = "gcpbuild-controller"
+const (
+	ControllerName = "gcpbuild-controller"
+
+	sourceBuildRefIndex = "spec.sourceBuildRef.name"
+
+	// serialOutputEventLines is the number of lines of the serial port output reported when a build fails.
+	serialOutputEventLines = 20
+)
 
 var rawLog *logr.Logger
 
 // GCPBuildReconciler reconciles a GCPBuild object
 type GCPBuildReconciler struct {
 	client.Client
-	log      logr.Logger
-	recorder record.EventRecorder
+	log       logr.Logger
+	recorder  record.EventRecorder
+	endpoints scope.ServiceEndpoints
 }
 
 func (r *GCPBuildReconciler) recordEvent(gcpBuild *infrav1.GCPBuild, eventType, reason, message string) {
@@ -72,6 +85,9 @@ func (r *GCPBuildReconciler) recordEvent(gcpBuild *infrav1.GCPBuild, eventType,
 // +kubebuilder:rbac:groups=infrastructure.forge.build,resources=gcpbuilds/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.forge.build,resources=gcpbuilds/finalizers,verbs=update
 // +kubebuilder:rbac:groups=forge.build,resources=builds,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *GCPBuildReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
 	r.log = rawLog.WithValues("gcpbuild", req.Name, "namespace", req.Namespace).WithName(ControllerName)
@@ -103,10 +119,12 @@ func (r *GCPBuildReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	}
 
 	buildScope, err := scope.NewBuildScope(ctx, scope.BuildScopeParams{
-		Client:   r.Client,
-		Build:    build,
-		GCPBuild: gcpBuild,
-		Log:      rawLog.WithValues("gcpbuild", req.Name, "namespace", req.Namespace),
+		Client:    r.Client,
+		Build:     build,
+		GCPBuild:  gcpBuild,
+		Endpoints: r.endpoints,
+		Recorder:  r.recorder,
+		Log:       rawLog.WithValues("gcpbuild", req.Name, "namespace", req.Namespace),
 	})
 	if err != nil {
 		return ctrl.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -121,18 +139,20 @@ func (r *GCPBuildReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 
 	// Handle deleted clusters
 	if !gcpBuild.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, r.reconcileDelete(ctx, buildScope)
+		return r.reconcileDelete(ctx, buildScope)
 	}
 
 	// Handle non-deleted clusters
 	return r.reconcileNormal(ctx, buildScope)
 }
 
-func (r *GCPBuildReconciler) reconcileDelete(ctx context.Context, buildScope *scope.BuildScope) error {
+func (r *GCPBuildReconciler) reconcileDelete(ctx context.Context, buildScope *scope.BuildScope) (ctrl.Result, error) {
 	r.log.Info("Reconciling Delete GCPBuild")
 
 	reconcilers := []cloud.Reconciler{
+		images.New(buildScope),
 		instances.New(buildScope),
+		oslogin.New(buildScope),
 		subnets.New(buildScope),
 		firewalls.New(buildScope),
 		networks.New(buildScope),
@@ -140,24 +160,45 @@ func (r *GCPBuildReconciler) reconcileDelete(ctx context.Context, buildScope *sc
 
 	for _, reconcile := range reconcilers {
 		if err := reconcile.Delete(ctx); err != nil {
+			// Resources are deleted asynchronously, check on the deletion later.
+			if result, ok := requeueInProgress(err); ok {
+				r.log.V(1).Info("Waiting for deletion", "reason", err.Error())
+				return result, nil
+			}
 			r.log.Error(err, "Reconcile error")
 			r.recordEvent(buildScope.GCPBuild, "Warning", "Cleaning Up Failed", fmt.Sprintf("Reconcile error - %v ", err))
-			return err
+			buildScope.MarkConditionFalse(infrav1.CleanedUpCondition, infrav1.CleanUpFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, err
 		}
 	}
 
-	controllerutil.RemoveFinalizer(buildScope.GCPBuild, infrav1.BuildFinalizer)
+	// Keep the finalizer after the post-build cleanup if the image has to be deleted together with the GCPBuild.
+	if buildScope.IsDeleting() || buildScope.ArtifactDeletionPolicy() != infrav1.ArtifactDeletionPolicyDelete {
+		controllerutil.RemoveFinalizer(buildScope.GCPBuild, infrav1.BuildFinalizer)
+	}
 	r.recordEvent(buildScope.GCPBuild, "Normal", "Reconciled", fmt.Sprintf("%s is reconciled successfully ", buildScope.GCPBuild.Name))
 	buildScope.SetCleanedUP()
-	return nil
+	return ctrl.Result{}, nil
 }
+
 func (r *GCPBuildReconciler) reconcileNormal(ctx context.Context, buildScope *scope.BuildScope) (ctrl.Result, error) {
 	r.log.Info("Reconciling GCPBuild")
 
+	if buildScope.HasFailed() {
+		// Retry the cleanup of a failed build until it succeeds, so that the builder instance and the network
+		// don't outlive the build.
+		if !buildScope.IsCleanedUP() {
+			return r.reconcileDelete(ctx, buildScope)
+		}
+		r.log.Info("GCPBuild has failed, won't reconcile", "reason", *buildScope.GCPBuild.Status.FailureReason)
+		return ctrl.Result{}, nil
+	}
+
 	reconcilers := []cloud.Reconciler{
 		networks.New(buildScope),
 		firewalls.New(buildScope),
 		subnets.New(buildScope),
+		oslogin.New(buildScope),
 		instances.New(buildScope),
 		images.New(buildScope),
 	}
@@ -169,11 +210,64 @@ func (r *GCPBuildReconciler) reconcileNormal(ctx context.Context, buildScope *sc
 
 	buildScope.SetSSHKey(sshKey)
 
+	if err := buildScope.ResolveEncryptionKeys(ctx); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "unable to resolve encryption keys")
+	}
+
+	if err := buildScope.EnsureWindowsKey(ctx); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "unable to get a windows key")
+	}
+
+	// Wait for the GCPBuild the source image comes from, the watch on GCPBuilds requeues once it is ready.
+	if !buildScope.IsReady() && buildScope.SourceImage() == nil {
+		ready, err := buildScope.ResolveSourceBuild(ctx)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "unable to resolve source build")
+		}
+		if buildScope.HasFailed() {
+			r.recordEvent(buildScope.GCPBuild, "Warning", "SourceBuildFailed", *buildScope.GCPBuild.Status.FailureMessage)
+			return r.reconcileDelete(ctx, buildScope)
+		}
+		if !ready {
+			r.recordEvent(buildScope.GCPBuild, "Normal", "WaitingForSourceBuild", fmt.Sprintf("Waiting for source build %s to be ready ", buildScope.SourceBuildRef().Name))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	buildScope.EnsureStartTime()
+	if !buildScope.IsReady() && buildScope.HasTimedOut() {
+		message := fmt.Sprintf("build did not complete within %s", buildScope.GCPBuild.Spec.Timeout.Duration)
+		r.recordEvent(buildScope.GCPBuild, "Warning", "BuildTimeout", message)
+
+		return r.failBuild(ctx, buildScope, infrav1.BuildTimeoutReason, message)
+	}
+
 	if !buildScope.IsReady() {
 		for _, reconciler := range reconcilers {
 			if err := reconciler.Reconcile(ctx); err != nil {
+				// The next reconcilers depend on the resources being created, check on them later.
+				if result, ok := requeueInProgress(err); ok {
+					r.log.V(1).Info("Waiting for creation", "reason", err.Error())
+					return result, nil
+				}
+				if errors.Is(err, instances.ErrInstancePreempted) {
+					r.recordEvent(buildScope.GCPBuild, "Warning", "InstancePreempted", err.Error())
+					return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+				}
+				if errors.Is(err, instances.ErrZoneResourcesExhausted) {
+					r.recordEvent(buildScope.GCPBuild, "Warning", "ZoneResourcesExhausted", err.Error())
+					return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+				}
 				r.log.Error(err, "Reconcile error")
 				r.recordEvent(buildScope.GCPBuild, "Warning", "Building Failed", fmt.Sprintf("Reconcile error - %v ", err))
+				// Retrying won't fix terminal errors, fail the build instead of requeueing.
+				if reason, ok := terminalFailureReason(err); ok {
+					return r.failBuild(ctx, buildScope, reason, err.Error())
+				}
+				if buildScope.HasFailed() {
+					r.recordSerialOutput(ctx, buildScope)
+					return r.reconcileDelete(ctx, buildScope)
+				}
 				return ctrl.Result{}, err
 			}
 		}
@@ -184,7 +278,7 @@ func (r *GCPBuildReconciler) reconcileNormal(ctx context.Context, buildScope *sc
 	}
 
 	if buildScope.IsReady() && !buildScope.IsCleanedUP() {
-		return ctrl.Result{}, r.reconcileDelete(ctx, buildScope)
+		return r.reconcileDelete(ctx, buildScope)
 	}
 
 	if buildScope.GetInstanceID() == nil {
@@ -195,6 +289,14 @@ func (r *GCPBuildReconciler) reconcileNormal(ctx context.Context, buildScope *sc
 
 	r.recordEvent(buildScope.GCPBuild, "Normal", "InstanceCreated", fmt.Sprintf("Machine is created, Got an instance ID - %s ", *buildScope.GetInstanceID()))
 
+	if !buildScope.IsInstanceReady() {
+		message := conditions.GetMessage(buildScope.GCPBuild, infrav1.InstanceReadyCondition)
+		buildScope.MarkConditionFalse(infrav1.MachineReadyCondition, infrav1.WaitingForInstanceReadyReason, clusterv1.ConditionSeverityInfo, message)
+		r.recordEvent(buildScope.GCPBuild, "Normal", "WaitingForInstance", fmt.Sprintf("Instance is not ready yet - %s ", message))
+
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
 	buildScope.SetMachineReady()
 
 	if buildScope.GCPBuild.Status.ArtifactRef == nil {
@@ -259,12 +361,91 @@ func scopeSSHKey(username, privateKey, pubKey string) scope.SSHKey {
 	}
 }
 
+// requeueInProgress returns the result to check on the running operation err reports, if any. The reconcilers
+// don't wait for the operations they start.
+func requeueInProgress(err error) (ctrl.Result, bool) {
+	if !errors.Is(err, operations.ErrInProgress) {
+		return ctrl.Result{}, false
+	}
+
+	return ctrl.Result{RequeueAfter: 5 * time.Second}, true
+}
+
+// terminalFailureReason returns the failure reason of a GCP error that retrying won't fix.
+func terminalFailureReason(err error) (string, bool) {
+	if !gcperrors.IsTerminal(err) {
+		return "", false
+	}
+
+	switch {
+	case gcperrors.IsPermissionDenied(err):
+		return infrav1.PermissionDeniedReason, true
+	case gcperrors.IsMissingResource(err):
+		return infrav1.ResourceNotFoundReason, true
+	case gcperrors.IsNameConflict(err):
+		return infrav1.NameConflictReason, true
+	default:
+		return infrav1.InvalidArgumentReason, true
+	}
+}
+
+// failBuild fails the build and cleans up its infrastructure. The serial port output of the builder instance is
+// captured first, it is lost once the instance is deleted.
+func (r *GCPBuildReconciler) failBuild(ctx context.Context, buildScope *scope.BuildScope, reason, message string) (ctrl.Result, error) {
+	instances.New(buildScope).CaptureSerialOutput(ctx)
+	buildScope.SetFailure(reason, message)
+	r.recordSerialOutput(ctx, buildScope)
+
+	return r.reconcileDelete(ctx, buildScope)
+}
+
+// recordSerialOutput emits the last lines of the serial port output of the builder instance as a Warning event.
+func (r *GCPBuildReconciler) recordSerialOutput(ctx context.Context, buildScope *scope.BuildScope) {
+	output, err := buildScope.SerialOutputLines(ctx, serialOutputEventLines)
+	if err != nil {
+		r.log.Error(err, "Unable to read serial port output")
+		return
+	}
+	if output == "" {
+		return
+	}
+
+	r.recordEvent(buildScope.GCPBuild, "Warning", "SerialOutput", fmt.Sprintf("Last lines of the serial port output:\n%s", output))
+}
+
+// sourceBuildToDependentBuilds maps a GCPBuild to the GCPBuilds using it as source build.
+func (r *GCPBuildReconciler) sourceBuildToDependentBuilds(ctx context.Context, obj client.Object) []ctrl.Request {
+	dependentBuilds := &infrav1.GCPBuildList{}
+	if err := r.List(ctx, dependentBuilds, client.InNamespace(obj.GetNamespace()), client.MatchingFields{sourceBuildRefIndex: obj.GetName()}); err != nil {
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(dependentBuilds.Items))
+	for _, dependentBuild := range dependentBuilds.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&dependentBuild)})
+	}
+
+	return requests
+}
+
 // Add creates a new GCPBuild controller and adds it to the Manager.
-func Add(ctx context.Context, mgr ctrl.Manager, numWorkers int, log *logr.Logger) error {
+func Add(ctx context.Context, mgr ctrl.Manager, numWorkers int, endpoints scope.ServiceEndpoints, log *logr.Logger) error {
 	// Create the reconciler instance
 	reconciler := &GCPBuildReconciler{
-		Client:   mgr.GetClient(),
-		recorder: mgr.GetEventRecorderFor(ControllerName),
+		Client:    mgr.GetClient(),
+		recorder:  mgr.GetEventRecorderFor(ControllerName),
+		endpoints: endpoints,
+	}
+
+	// Index GCPBuilds by source build, so that dependent builds are requeued once their source build is ready.
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &infrav1.GCPBuild{}, sourceBuildRefIndex, func(obj client.Object) []string {
+		gcpBuild := obj.(*infrav1.GCPBuild)
+		if gcpBuild.Spec.SourceBuildRef == nil {
+			return nil
+		}
+		return []string{gcpBuild.Spec.SourceBuildRef.Name}
+	}); err != nil {
+		return errors.Wrap(err, "failed to index GCPBuilds by source build")
 	}
 
 	// Set up the controller with custom predicates
@@ -278,6 +459,8 @@ func Add(ctx context.Context, mgr ctrl.Manager, numWorkers int, log *logr.Logger
 		Watches(&buildv1.Build{},
 			handler.EnqueueRequestsFromMapFunc(forgeutil.BuildToInfrastructureMapFunc(ctx, infrav1.GroupVersion.WithKind(infrav1.GCPBuildKind), mgr.GetClient(), &infrav1.GCPBuild{})),
 			builder.WithPredicates(predicates.BuildUnpaused(ctrl.LoggerFrom(ctx)))).
+		Watches(&infrav1.GCPBuild{},
+			handler.EnqueueRequestsFromMapFunc(reconciler.sourceBuildToDependentBuilds)).
 		Build(reconciler)
 
 	rawLog = log
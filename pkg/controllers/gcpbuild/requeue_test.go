@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Forge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcpbuild
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/forge-build/forge-provider-gcp/pkg/cloud/gcp/compute/operations"
+)
+
+func TestRequeueInProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantResult  ctrl.Result
+		wantRequeue bool
+	}{
+		{
+			name:        "operation in progress",
+			err:         operations.ErrInProgress,
+			wantResult:  ctrl.Result{RequeueAfter: 5 * time.Second},
+			wantRequeue: true,
+		},
+		{
+			name:        "wrapped by a reconciler",
+			err:         errors.Wrapf(operations.ErrInProgress, "firewall rules %v are being created", []string{"allow-ssh"}),
+			wantResult:  ctrl.Result{RequeueAfter: 5 * time.Second},
+			wantRequeue: true,
+		},
+		{
+			name:        "wrapped twice",
+			err:         fmt.Errorf("reconcile failed: %w", errors.Wrap(operations.ErrInProgress, "subnet is being deleted")),
+			wantResult:  ctrl.Result{RequeueAfter: 5 * time.Second},
+			wantRequeue: true,
+		},
+		{
+			name: "operation failed",
+			err:  fmt.Errorf("operation insert instances/my-build failed: %w", &googleapi.Error{Code: http.StatusForbidden}),
+		},
+		{
+			name: "other error",
+			err:  errors.New("operation in progress"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, requeue := requeueInProgress(tt.err)
+			g.Expect(requeue).To(Equal(tt.wantRequeue))
+			g.Expect(result).To(Equal(tt.wantResult))
+		})
+	}
+}